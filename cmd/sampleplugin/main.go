@@ -0,0 +1,83 @@
+// Command sampleplugin is a minimal out-of-process exchange adapter. It
+// serves made up rates for a few assets and shows what a plugin has to
+// implement, run it through the tracker with an exchange block like:
+//
+//	sample:
+//	  type: plugin
+//	  is_active: true
+//	  options:
+//	    command: ./bin/sampleplugin
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"github.com/fiensola/funding/internal/exchange/plugin"
+)
+
+const fundingInterval = time.Hour
+
+var prices = map[string]float64{
+	"BTC-PERP": 65000,
+	"ETH-PERP": 3200,
+	"SOL-PERP": 150,
+}
+
+type sample struct{}
+
+func (sample) Name() string {
+	return "sample"
+}
+
+func (sample) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	now := time.Now()
+
+	rates := make([]domain.FundingRate, 0, len(prices))
+	for symbol, price := range prices {
+		rates = append(rates, rateAt(symbol, price, now))
+	}
+
+	return rates, nil
+}
+
+// FetchFundingHistory makes the plugin report CAPABILITY_HISTORY.
+func (sample) FetchFundingHistory(ctx context.Context, symbol string, from time.Time) ([]domain.FundingRate, error) {
+	price, ok := prices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("unknown symbol %s", symbol)
+	}
+
+	var rates []domain.FundingRate
+	for ts := from.Truncate(fundingInterval); ts.Before(time.Now()); ts = ts.Add(fundingInterval) {
+		rates = append(rates, rateAt(symbol, price, ts))
+	}
+
+	return rates, nil
+}
+
+// rateAt swings the rate over a day so the output changes between fetches.
+func rateAt(symbol string, price float64, ts time.Time) domain.FundingRate {
+	phase := float64(ts.Unix()%86400) / 86400 * 2 * math.Pi
+
+	return domain.FundingRate{
+		VenueSymbol:     symbol,
+		Rate:            0.0001 * math.Sin(phase),
+		FundingInterval: fundingInterval,
+		Price:           &price,
+		Timestamp:       ts,
+		NextFunding:     exchange.NextSettlement(ts, fundingInterval),
+	}
+}
+
+func main() {
+	if err := plugin.Serve(sample{}); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fiensola/funding/internal/exchange"
+	"github.com/fiensola/funding/internal/service"
+	"go.uber.org/zap"
+)
+
+// runBackfill stores the settled funding history of the named exchange for
+// the given venue symbols. It talks to the adapter directly, the circuit
+// breaker only guards the collection loop.
+func runBackfill(
+	ctx context.Context,
+	tracker *service.TrackerService,
+	ex exchange.Exchange,
+	symbols string,
+	since time.Duration,
+	logger *zap.Logger,
+) error {
+	history, ok := ex.(exchange.HistoryExchange)
+	if !ok {
+		return fmt.Errorf("exchange %q has no funding history", ex.Name())
+	}
+
+	var list []string
+	for _, symbol := range strings.Split(symbols, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			list = append(list, symbol)
+		}
+	}
+	if len(list) == 0 {
+		return fmt.Errorf("-backfill needs -symbols")
+	}
+
+	logger.Info("backfilling funding history",
+		zap.String("exchange", ex.Name()),
+		zap.Strings("symbols", list),
+		zap.Duration("since", since),
+	)
+
+	stored, err := tracker.Backfill(ctx, history, list, time.Now().Add(-since))
+	fmt.Fprintf(os.Stderr, "%s: %d settled rates in the window, settlements stored before were skipped\n", ex.Name(), stored)
+
+	return err
+}
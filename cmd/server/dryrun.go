@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fiensola/funding/internal/config"
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"github.com/fiensola/funding/internal/symbol"
+	"github.com/fiensola/funding/internal/validation"
+	"go.uber.org/zap"
+)
+
+// dryRow is a parsed rate as printed by -dry-run.
+type dryRow struct {
+	domain.FundingRate
+	Issues []domain.FieldIssue `json:"issues,omitempty"`
+}
+
+// runDry fetches one exchange, active or not, and prints the rows the
+// tracker would store as JSON lines, with the issues of rows it would
+// quarantine. It needs no database, so stored aliases are not applied.
+func runDry(ctx context.Context, cfg *config.Config, name string, logger *zap.Logger) error {
+	ex, err := buildExchange(cfg, name, logger)
+	if err != nil {
+		return err
+	}
+	defer closeExchanges([]exchange.Exchange{ex}, logger)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	rates, err := ex.FetchFundingRates(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", name, err)
+	}
+
+	for i := range rates {
+		if rates[i].Source == "" {
+			rates[i].Source = rates[i].Exchange
+		}
+	}
+	symbol.NewNormalizer(nil, logger).Apply(rates)
+
+	validator := validation.NewValidator(validation.Config{MaxHourlyRate: cfg.Tracker.MaxHourlyRate})
+
+	encoder := json.NewEncoder(os.Stdout)
+	rejected := 0
+	for _, rate := range rates {
+		// validate row by row to keep the issues next to the row
+		_, issues := validator.Validate([]domain.FundingRate{rate})
+		row := dryRow{FundingRate: rate}
+		for _, issue := range issues {
+			row.Issues = append(row.Issues, domain.FieldIssue{Field: issue.Field, Raw: issue.RawValue, Reason: issue.Reason})
+		}
+		if len(row.Issues) > 0 {
+			rejected++
+		}
+
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("print row: %w", err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: %d rows, %d would be quarantined\n", name, len(rates), rejected)
+
+	return nil
+}
@@ -2,24 +2,26 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
 	"time"
 
 	"github.com/fiensola/funding/internal/api"
 	"github.com/fiensola/funding/internal/config"
+	"github.com/fiensola/funding/internal/event"
 	"github.com/fiensola/funding/internal/exchange"
-	"github.com/fiensola/funding/internal/exchange/backpack"
-	"github.com/fiensola/funding/internal/exchange/extended"
-	"github.com/fiensola/funding/internal/exchange/hibachi"
-	"github.com/fiensola/funding/internal/exchange/lighter"
-	"github.com/fiensola/funding/internal/exchange/pacifica"
+	_ "github.com/fiensola/funding/internal/exchange/all"
 	"github.com/fiensola/funding/internal/logger"
 	"github.com/fiensola/funding/internal/repository/postgres"
 	"github.com/fiensola/funding/internal/service"
+	"github.com/fiensola/funding/internal/symbol"
+	"github.com/fiensola/funding/internal/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
@@ -33,6 +35,12 @@ func main() {
 }
 
 func run() error {
+	dryRun := flag.String("dry-run", "", "fetch the named exchange once, print the parsed rows and exit")
+	backfill := flag.String("backfill", "", "store the settled funding history of the named exchange and exit")
+	backfillSymbols := flag.String("symbols", "", "comma separated venue symbols for -backfill")
+	backfillSince := flag.Duration("since", 30*24*time.Hour, "how far back -backfill goes")
+	flag.Parse()
+
 	//config
 	cfg, err := config.Load(".")
 	if err != nil {
@@ -46,10 +54,15 @@ func run() error {
 	}
 	defer logger.Sync()
 
+	ctx := context.Background()
+
+	if *dryRun != "" {
+		return runDry(ctx, cfg, *dryRun, logger)
+	}
+
 	logger.Info("starting funding service tracker")
 
 	//db
-	ctx := context.Background()
 	dbPool, err := pgxpool.New(ctx, cfg.Database.DSN())
 	if err != nil {
 		return fmt.Errorf("connect to database: %w", err)
@@ -65,51 +78,117 @@ func run() error {
 
 	//repos
 	fundingRepo := postgres.NewFundingRepository(dbPool, logger)
+	aliasRepo := postgres.NewAliasRepository(dbPool, logger)
+	quarantineRepo := postgres.NewQuarantineRepository(dbPool, logger)
+	leaseRepo := postgres.NewLeaseRepository(dbPool, logger)
+	runRepo := postgres.NewRunRepository(dbPool, logger)
+
+	//symbols
+	normalizer := symbol.NewNormalizer(aliasRepo, logger)
+	if err := normalizer.Reload(ctx); err != nil {
+		return fmt.Errorf("init symbol aliases: %w", err)
+	}
+
+	if *backfill != "" {
+		ex, err := buildExchange(cfg, *backfill, logger)
+		if err != nil {
+			return err
+		}
+		defer closeExchanges([]exchange.Exchange{ex}, logger)
+
+		tracker := service.NewTrackerService(nil, fundingRepo, quarantineRepo, runRepo, normalizer, nil, logger, service.TrackerConfig{
+			Validation: validation.Config{
+				MaxHourlyRate: cfg.Tracker.MaxHourlyRate,
+			},
+		})
+
+		return runBackfill(ctx, tracker, ex, *backfillSymbols, *backfillSince, logger)
+	}
 
 	//exchanges
-	exchanges := []exchange.Exchange{
-		pacifica.NewClient(exchange.Config{
-			BaseURL:  cfg.Exchages.Pacifica.BaseURL,
-			Proxy:    cfg.Proxy,
-			IsActive: cfg.Exchages.Pacifica.IsActive,
-		}, logger),
-		lighter.NewClient(exchange.Config{
-			BaseURL:  cfg.Exchages.Lighter.BaseURL,
-			Proxy:    cfg.Exchages.Lighter.Proxy,
-			IsActive: cfg.Exchages.Lighter.IsActive,
-		}, logger),
-		extended.NewClient(exchange.Config{
-			BaseURL:  cfg.Exchages.Extended.BaseURL,
-			Proxy:    cfg.Proxy,
-			IsActive: cfg.Exchages.Extended.IsActive,
-		}, logger),
-		hibachi.NewClient(exchange.Config{
-			BaseURL:  cfg.Exchages.Hibachi.BaseURL,
-			Proxy:    cfg.Proxy,
-			IsActive: cfg.Exchages.Hibachi.IsActive,
-		}, logger),
-		backpack.NewClient(exchange.Config{
-			BaseURL:  cfg.Exchages.Backpack.BaseURL,
-			Proxy:    cfg.Proxy,
-			IsActive: cfg.Exchages.Backpack.IsActive,
-		}, logger),
+	exchanges, err := buildExchanges(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("init exchanges: %w", err)
+	}
+
+	//events
+	bus := event.NewBus(logger)
+	if err := bus.Subscribe("listings", 0, logListings(logger), event.TypeSymbolListed, event.TypeSymbolDelisted); err != nil {
+		return fmt.Errorf("init events: %w", err)
 	}
 
 	//tracker service
+	schedules := make(map[string]service.Schedule, len(cfg.Exchanges))
+	for name, exCfg := range cfg.Exchanges {
+		schedules[name] = service.Schedule{
+			Interval:           exCfg.UpdateInterval,
+			SettlementSampling: exCfg.SettlementSampling.Enabled,
+			Before:             exCfg.SettlementSampling.Before,
+			After:              exCfg.SettlementSampling.After,
+		}
+	}
+
 	tracker := service.NewTrackerService(
 		exchanges,
 		fundingRepo,
+		quarantineRepo,
+		runRepo,
+		normalizer,
+		bus,
 		logger,
-		cfg.Tracker.UpdateInterval,
+		service.TrackerConfig{
+			Interval:            cfg.Tracker.UpdateInterval,
+			StreamFlushInterval: cfg.Tracker.StreamFlushInterval,
+			Validation: validation.Config{
+				MaxHourlyRate: cfg.Tracker.MaxHourlyRate,
+			},
+			Breaker: exchange.BreakerConfig{
+				FailureThreshold:  cfg.Tracker.Breaker.FailureThreshold,
+				OpenTimeout:       cfg.Tracker.Breaker.OpenTimeout,
+				HalfOpenSuccesses: cfg.Tracker.Breaker.HalfOpenSuccesses,
+			},
+			Schedules: schedules,
+		},
 	)
 
-	go tracker.Start(ctx)
+	if err := tracker.LoadLatest(ctx); err != nil {
+		return fmt.Errorf("init latest rates: %w", err)
+	}
+
+	//leader election
+	var elector *service.LeaderElector
+	if cfg.Tracker.LeaderElection.Enabled {
+		elector, err = service.NewLeaderElector(leaseRepo, logger, service.ElectionConfig{
+			InstanceID: cfg.Tracker.LeaderElection.InstanceID,
+			LeaseTTL:   cfg.Tracker.LeaderElection.LeaseTTL,
+		})
+		if err != nil {
+			return fmt.Errorf("init leader election: %w", err)
+		}
+	}
+
+	runCtx, stopRun := context.WithCancel(ctx)
+	if elector != nil {
+		// followers serve the rates the leader stores
+		go tracker.FollowLatest(runCtx, cfg.Tracker.UpdateInterval, func() bool {
+			return !elector.IsLeader()
+		})
+	}
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		if elector != nil {
+			elector.Run(runCtx, tracker.Start)
+			return
+		}
+		tracker.Start(runCtx)
+	}()
 
 	//router
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	router.Use(gin.Recovery())
-	handler := api.NewHandler(tracker, logger)
+	handler := api.NewHandler(tracker, normalizer, elector, cfg.Server.AdminToken, logger)
 	handler.RegisterRoutes(router)
 
 	//http server
@@ -140,6 +219,14 @@ func run() error {
 	defer cancel()
 
 	tracker.Stop()
+	stopRun()
+	// lets the leader release its lease
+	select {
+	case <-runDone:
+	case <-shutdownCtx.Done():
+	}
+	bus.Close()
+	closeExchanges(exchanges, logger)
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		return fmt.Errorf("server shutdown: %w", err)
@@ -149,3 +236,96 @@ func run() error {
 
 	return nil
 }
+
+// logListings logs symbols appearing on and disappearing from venues.
+func logListings(logger *zap.Logger) event.Handler {
+	return func(e event.Event) {
+		switch e := e.(type) {
+		case event.SymbolListed:
+			logger.Info("symbol listed",
+				zap.String("exchange", e.Exchange),
+				zap.String("symbol", e.Symbol),
+				zap.String("venue_symbol", e.VenueSymbol),
+			)
+		case event.SymbolDelisted:
+			logger.Info("symbol delisted",
+				zap.String("exchange", e.Exchange),
+				zap.String("symbol", e.Symbol),
+				zap.String("venue_symbol", e.VenueSymbol),
+			)
+		}
+	}
+}
+
+// buildExchanges creates a client for every active exchange in the config.
+func buildExchanges(cfg *config.Config, logger *zap.Logger) ([]exchange.Exchange, error) {
+	names := make([]string, 0, len(cfg.Exchanges))
+	for name := range cfg.Exchanges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	exchanges := make([]exchange.Exchange, 0, len(names))
+	for _, name := range names {
+		if !cfg.Exchanges[name].IsActive {
+			continue
+		}
+
+		ex, err := buildExchange(cfg, name, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		exchanges = append(exchanges, ex)
+	}
+
+	return exchanges, nil
+}
+
+// closeExchanges releases exchanges holding resources, such as plugin
+// processes.
+func closeExchanges(exchanges []exchange.Exchange, logger *zap.Logger) {
+	for _, ex := range exchanges {
+		if closer, ok := ex.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				logger.Warn("failed to close exchange", zap.String("exchange", ex.Name()), zap.Error(err))
+			}
+		}
+	}
+}
+
+// buildExchange creates the client of one exchange config block.
+func buildExchange(cfg *config.Config, name string, logger *zap.Logger) (exchange.Exchange, error) {
+	exCfg, ok := cfg.Exchanges[name]
+	if !ok {
+		return nil, fmt.Errorf("exchange %q is not configured", name)
+	}
+
+	adapter := exCfg.Type
+	if adapter == "" {
+		adapter = name
+	}
+
+	proxy := exCfg.Proxy
+	if proxy == "" {
+		proxy = cfg.Proxy
+	}
+
+	httpCfg := cfg.HTTP.Merge(exCfg.HTTP)
+
+	return exchange.New(adapter, exchange.Config{
+		Name:     name,
+		BaseURL:  exCfg.BaseURL,
+		Proxy:    proxy,
+		IsActive: exCfg.IsActive,
+		HTTP: exchange.HTTPConfig{
+			Timeout:     httpCfg.Timeout,
+			MaxAttempts: httpCfg.MaxAttempts,
+			BaseDelay:   httpCfg.BaseDelay,
+			MaxDelay:    httpCfg.MaxDelay,
+			RateLimit:   httpCfg.RateLimit,
+			Burst:       httpCfg.Burst,
+		},
+		Options: exCfg.Options,
+	}, logger)
+}
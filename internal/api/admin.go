@@ -0,0 +1,82 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/repository"
+	"github.com/fiensola/funding/internal/symbol"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func (h *Handler) requireAdmin(c *gin.Context) {
+	token := c.GetHeader("X-Admin-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	c.Next()
+}
+
+func (h *Handler) ListAliases(c *gin.Context) {
+	aliases, err := h.normalizer.List(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to list symbol aliases", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  aliases,
+		"count": len(aliases),
+	})
+}
+
+func (h *Handler) SaveAlias(c *gin.Context) {
+	var alias domain.SymbolAlias
+	if err := c.ShouldBindJSON(&alias); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.normalizer.Save(c.Request.Context(), alias)
+	if errors.Is(err, symbol.ErrInvalidAlias) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to save symbol alias", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteAlias takes the venue symbol as a wildcard, venue symbols such as
+// BTC/USDT contain slashes.
+func (h *Handler) DeleteAlias(c *gin.Context) {
+	venueSymbol := strings.TrimPrefix(c.Param("venue_symbol"), "/")
+	if venueSymbol == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "venue_symbol is required"})
+		return
+	}
+
+	err := h.normalizer.Delete(c.Request.Context(), c.Param("exchange"), venueSymbol)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "alias not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to delete symbol alias", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
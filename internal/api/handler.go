@@ -1,39 +1,70 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/repository"
 	"github.com/fiensola/funding/internal/service"
+	"github.com/fiensola/funding/internal/symbol"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 type Handler struct {
-	tracker *service.TrackerService
-	logger  *zap.Logger
+	tracker    *service.TrackerService
+	normalizer *symbol.Normalizer
+	elector    *service.LeaderElector // nil when leader election is off
+	adminToken string
+	logger     *zap.Logger
 }
 
-func NewHandler(tracker *service.TrackerService, logger *zap.Logger) *Handler {
+func NewHandler(
+	tracker *service.TrackerService,
+	normalizer *symbol.Normalizer,
+	elector *service.LeaderElector,
+	adminToken string,
+	logger *zap.Logger,
+) *Handler {
 	return &Handler{
-		tracker: tracker,
-		logger:  logger,
+		tracker:    tracker,
+		normalizer: normalizer,
+		elector:    elector,
+		adminToken: adminToken,
+		logger:     logger,
 	}
 }
 
+// SideRates are the per side funding and borrow rates of asymmetric venues,
+// in percent per unit like the main rate.
+type SideRates struct {
+	Long        *float64 `json:"long"`
+	Short       *float64 `json:"short"`
+	LongBorrow  *float64 `json:"long_borrow,omitempty"`
+	ShortBorrow *float64 `json:"short_borrow,omitempty"`
+}
+
 type Symbol struct {
-	Exchanges map[string]float64   `json:"exchanges"`
-	UpdatedAt map[string]time.Time `json:"updated_at"`
+	Exchanges   map[string]float64                `json:"exchanges"`
+	Sources     map[string]string                 `json:"sources,omitempty"` // supplier of rows taken from another venue's feed
+	Sides       map[string]SideRates              `json:"sides,omitempty"`   // only venues with asymmetric funding
+	Intervals   map[string]float64                `json:"intervals"`         // funding interval in hours
+	Prices      map[string]*float64               `json:"prices"`
+	Markets     map[string]*domain.MarketSnapshot `json:"markets"`
+	NextFunding map[string]*time.Time             `json:"next_funding"`
+	UpdatedAt   map[string]time.Time              `json:"updated_at"`
 }
 
 func (h *Handler) RegisterRoutes(r *gin.Engine) {
 	//cors
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Admin-Token")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -46,6 +77,18 @@ func (h *Handler) RegisterRoutes(r *gin.Engine) {
 	api := r.Group("/api/v1")
 	{
 		api.GET("/funding-rates", h.GetFundingRates)
+		api.GET("/exchanges/health", h.GetExchangesHealth)
+		api.GET("/status", h.GetStatus)
+		api.GET("/runs", h.GetRuns)
+	}
+
+	if h.adminToken != "" {
+		admin := api.Group("/admin", h.requireAdmin)
+		{
+			admin.GET("/aliases", h.ListAliases)
+			admin.PUT("/aliases", h.SaveAlias)
+			admin.DELETE("/aliases/:exchange/*venue_symbol", h.DeleteAlias)
+		}
 	}
 	r.Static("/assets", "./web/build/assets")
 	r.StaticFile("/", "./web/build/index.html")
@@ -61,6 +104,15 @@ func (h *Handler) GetFundingRates(c *gin.Context) {
 		filter.Exchange = &exchange
 	}
 
+	if source := c.Query("source"); source != "" {
+		filter.Source = &source
+	}
+
+	if sampleType := c.Query("sample_type"); sampleType != "" {
+		value := domain.SampleType(sampleType)
+		filter.SampleType = &value
+	}
+
 	if symbol := c.Query("symbol"); symbol != "" {
 		filter.Symbol = &symbol
 	}
@@ -79,8 +131,26 @@ func (h *Handler) GetFundingRates(c *gin.Context) {
 		}
 	}
 
+	if minOI := c.Query("min_open_interest"); minOI != "" {
+		if value, err := strconv.ParseFloat(minOI, 64); err == nil {
+			filter.MinOpenInterest = &value
+		}
+	}
+
+	if minVolume := c.Query("min_volume"); minVolume != "" {
+		if value, err := strconv.ParseFloat(minVolume, 64); err == nil {
+			filter.MinVolume24h = &value
+		}
+	}
+
+	unit, err := domain.ParseRateUnit(c.DefaultQuery("unit", string(domain.RatePerHour)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	filter.SortBy = c.DefaultQuery("sort_by", "timestamp")
-	filter.SortBy = c.DefaultQuery("sort_order", "desc")
+	filter.SortOrder = c.DefaultQuery("sort_order", "desc")
 
 	rates, err := h.tracker.GetLatestRates(c.Request.Context(), filter)
 	if err != nil {
@@ -89,28 +159,185 @@ func (h *Handler) GetFundingRates(c *gin.Context) {
 		return
 	}
 
-	//format for frontend
+	//format for frontend, rates are in percent per unit
 	symbols := make(map[string]Symbol)
 	for _, rate := range rates {
 		if _, ok := symbols[rate.Symbol]; !ok {
-			inner := Symbol{
-				Exchanges: map[string]float64{
-					rate.Exchange: rate.Rate * 100,
-				},
-				UpdatedAt: map[string]time.Time{
-					rate.Exchange: rate.Timestamp,
-				},
+			symbols[rate.Symbol] = Symbol{
+				Exchanges:   make(map[string]float64),
+				Sources:     make(map[string]string),
+				Sides:       make(map[string]SideRates),
+				Intervals:   make(map[string]float64),
+				Prices:      make(map[string]*float64),
+				Markets:     make(map[string]*domain.MarketSnapshot),
+				NextFunding: make(map[string]*time.Time),
+				UpdatedAt:   make(map[string]time.Time),
 			}
-			symbols[rate.Symbol] = inner
+		}
+
+		// a venue's own adapter wins over rows from another venue's feed,
+		// of several contracts of one venue the first in sort order wins
+		_, seen := symbols[rate.Symbol].Exchanges[rate.Exchange]
+		_, seenSecondary := symbols[rate.Symbol].Sources[rate.Exchange]
+		if seen && !seenSecondary {
+			continue
+		}
+		if rate.IsSecondary() {
+			symbols[rate.Symbol].Sources[rate.Exchange] = rate.Source
 		} else {
-			symbols[rate.Symbol].Exchanges[rate.Exchange] = rate.Rate * 100
-			symbols[rate.Symbol].UpdatedAt[rate.Exchange] = rate.Timestamp
+			delete(symbols[rate.Symbol].Sources, rate.Exchange)
 		}
 
+		symbols[rate.Symbol].Exchanges[rate.Exchange] = rate.RateIn(unit) * 100
+		symbols[rate.Symbol].Intervals[rate.Exchange] = rate.FundingInterval.Hours()
+		if rate.LongRate != nil || rate.ShortRate != nil {
+			long, short, longBorrow, shortBorrow := rate.SideRatesIn(unit)
+			symbols[rate.Symbol].Sides[rate.Exchange] = SideRates{
+				Long:        percent(long),
+				Short:       percent(short),
+				LongBorrow:  percent(longBorrow),
+				ShortBorrow: percent(shortBorrow),
+			}
+		}
+		symbols[rate.Symbol].Prices[rate.Exchange] = rate.Price
+		symbols[rate.Symbol].Markets[rate.Exchange] = rate.Market
+		symbols[rate.Symbol].NextFunding[rate.Exchange] = rate.NextFunding
+		symbols[rate.Symbol].UpdatedAt[rate.Exchange] = rate.Timestamp
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":  symbols,
 		"count": len(symbols),
+		"unit":  unit,
+	})
+}
+
+func percent(rate *float64) *float64 {
+	if rate == nil {
+		return nil
+	}
+
+	value := *rate * 100
+
+	return &value
+}
+
+func (h *Handler) GetExchangesHealth(c *gin.Context) {
+	health := h.tracker.Health()
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  health,
+		"count": len(health),
+	})
+}
+
+// GetStatus reports whether this replica collects and which one leads.
+func (h *Handler) GetStatus(c *gin.Context) {
+	if h.elector == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"leader_election": false,
+			"leader":          true,
+		})
+		return
+	}
+
+	status := gin.H{
+		"leader_election": true,
+		"instance":        h.elector.ID(),
+		"leader":          h.elector.IsLeader(),
+	}
+
+	lease, err := h.elector.Leader(c.Request.Context())
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		h.logger.Error("failed to get leader lease", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	if err == nil {
+		status["lease"] = lease
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// GetRuns lists the fetch run journal, newest first.
+func (h *Handler) GetRuns(c *gin.Context) {
+	filter := domain.FetchRunFilter{Limit: 100}
+
+	if exchange := c.Query("exchange"); exchange != "" {
+		filter.Exchange = &exchange
+	}
+
+	if sampleType := c.Query("sample_type"); sampleType != "" {
+		value := domain.SampleType(sampleType)
+		filter.SampleType = &value
+	}
+
+	if errorClass := c.Query("error_class"); errorClass != "" {
+		value := domain.RunErrorClass(errorClass)
+		filter.ErrorClass = &value
+	}
+
+	if failed, err := strconv.ParseBool(c.Query("failed")); err == nil {
+		filter.Failed = failed
+	}
+
+	var err error
+	if filter.Since, err = timeQuery(c, "since"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.Until, err = timeQuery(c, "until"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if lim, err := strconv.Atoi(limit); err == nil && lim > 0 {
+			filter.Limit = min(lim, 1000)
+		}
+	}
+
+	if offset := c.Query("offset"); offset != "" {
+		if off, err := strconv.Atoi(offset); err == nil {
+			filter.Offset = off
+		}
+	}
+
+	runs, err := h.tracker.ListRuns(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to list fetch runs", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	type run struct {
+		domain.FetchRun
+		DurationMs int64 `json:"duration_ms"`
+	}
+
+	data := make([]run, 0, len(runs))
+	for _, r := range runs {
+		data = append(data, run{FetchRun: r, DurationMs: r.Duration().Milliseconds()})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"count": len(data),
 	})
 }
+
+// timeQuery parses an RFC 3339 query parameter, nil when it is unset.
+func timeQuery(c *gin.Context, param string) (*time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	ts, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, want RFC 3339 time", param)
+	}
+
+	return &ts, nil
+}
@@ -10,38 +10,35 @@ import (
 type Config struct {
 	Server struct {
 		Port int `mapstructure:"port"`
+		// AdminToken protects the admin API, empty disables the admin API.
+		AdminToken string `mapstructure:"admin_token"`
 	} `mapstructure:"server"`
 
 	Database DatabaseConfig `mapstructure:"db"`
 
-	Exchages struct {
-		Pacifica struct {
-			IsActive bool   `mapstructure:"is_active"`
-			BaseURL  string `mapstructure:"base_url"`
-		} `mapstructure:"pacifica"`
-		Lighter struct {
-			IsActive bool   `mapstructure:"is_active"`
-			BaseURL  string `mapstructure:"base_url"`
-			Proxy    string `mapstructure:"proxy"`
-		} `mapstructure:"lighter"`
-		Extended struct {
-			IsActive bool   `mapstructure:"is_active"`
-			BaseURL  string `mapstructure:"base_url"`
-		} `mapstructure:"extended"`
-		Hibachi struct {
-			IsActive bool   `mapstructure:"is_active"`
-			BaseURL  string `mapstructure:"base_url"`
-		} `mapstructure:"hibachi"`
-		Backpack struct {
-			IsActive bool   `mapstructure:"is_active"`
-			BaseURL  string `mapstructure:"base_url"`
-		} `mapstructure:"backpack"`
-	} `mapstructure:"exchanges"`
+	Exchanges map[string]ExchangeConfig `mapstructure:"exchanges"`
 
 	Proxy string `mapstructure:"proxy"`
 
+	// HTTP holds the defaults for exchange http clients.
+	HTTP HTTPConfig `mapstructure:"http"`
+
 	Tracker struct {
-		UpdateInterval time.Duration `mapstructure:"update_interval"`
+		UpdateInterval      time.Duration `mapstructure:"update_interval"`
+		StreamFlushInterval time.Duration `mapstructure:"stream_flush_interval"`
+		MaxHourlyRate       float64       `mapstructure:"max_hourly_rate"`
+		// LeaderElection lets replicas share a database with one of them
+		// collecting at a time.
+		LeaderElection struct {
+			Enabled    bool          `mapstructure:"enabled"`
+			InstanceID string        `mapstructure:"instance_id"`
+			LeaseTTL   time.Duration `mapstructure:"lease_ttl"`
+		} `mapstructure:"leader_election"`
+		Breaker struct {
+			FailureThreshold  int           `mapstructure:"failure_threshold"`
+			OpenTimeout       time.Duration `mapstructure:"open_timeout"`
+			HalfOpenSuccesses int           `mapstructure:"half_open_successes"`
+		} `mapstructure:"circuit_breaker"`
 	} `mapstructure:"tracker"`
 
 	Logger struct {
@@ -50,6 +47,59 @@ type Config struct {
 	} `mapstructure:"log"`
 }
 
+type ExchangeConfig struct {
+	// Type is the adapter to build, it defaults to the exchange name.
+	Type     string `mapstructure:"type"`
+	IsActive bool   `mapstructure:"is_active"`
+	BaseURL  string `mapstructure:"base_url"`
+	// Proxy overrides the global proxy for this exchange.
+	Proxy string `mapstructure:"proxy"`
+	// HTTP overrides the non zero fields of the global http config.
+	HTTP HTTPConfig `mapstructure:"http"`
+	// UpdateInterval overrides tracker.update_interval for this exchange.
+	UpdateInterval time.Duration `mapstructure:"update_interval"`
+	// SettlementSampling adds samples around every funding settlement.
+	SettlementSampling struct {
+		Enabled bool          `mapstructure:"enabled"`
+		Before  time.Duration `mapstructure:"before"`
+		After   time.Duration `mapstructure:"after"`
+	} `mapstructure:"settlement_sampling"`
+	Options map[string]any `mapstructure:"options"`
+}
+
+type HTTPConfig struct {
+	Timeout     time.Duration `mapstructure:"timeout"`
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	BaseDelay   time.Duration `mapstructure:"base_delay"`
+	MaxDelay    time.Duration `mapstructure:"max_delay"`
+	RateLimit   float64       `mapstructure:"rate_limit"` // requests per second per host
+	Burst       int           `mapstructure:"burst"`
+}
+
+// Merge returns h with the non zero fields of override applied.
+func (h HTTPConfig) Merge(override HTTPConfig) HTTPConfig {
+	if override.Timeout > 0 {
+		h.Timeout = override.Timeout
+	}
+	if override.MaxAttempts > 0 {
+		h.MaxAttempts = override.MaxAttempts
+	}
+	if override.BaseDelay > 0 {
+		h.BaseDelay = override.BaseDelay
+	}
+	if override.MaxDelay > 0 {
+		h.MaxDelay = override.MaxDelay
+	}
+	if override.RateLimit > 0 {
+		h.RateLimit = override.RateLimit
+	}
+	if override.Burst > 0 {
+		h.Burst = override.Burst
+	}
+
+	return h
+}
+
 type DatabaseConfig struct {
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
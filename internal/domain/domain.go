@@ -1,27 +1,258 @@
 package domain
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// FundingRate is a funding sample of one market. A positive Rate means longs
+// pay shorts. Venues that charge each side differently also set LongRate and
+// ShortRate, positive when that side pays, and borrow rates that are always
+// paid on top of funding.
+//
+// Exchange is the venue the rate describes and Source the adapter that
+// supplied it. They differ for rows taken from another venue's cross-venue
+// feed, such rows are secondary to the venue's own adapter.
 type FundingRate struct {
-	ID          uuid.UUID  `json:"id" db:"id"`
-	Exchange    string     `json:"exchange" db:"exchange"`
-	Symbol      string     `json:"symbol" db:"symbol"`
-	Price       *float64   `json:"price,omitempty" db:"price"`
-	Rate        float64    `json:"rate" db:"rate"`
-	Timestamp   time.Time  `json:"timestamp" db:"timestamp"`
-	NextFunding *time.Time `json:"next_funding,omitempty" db:"next_funding"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ID              uuid.UUID       `json:"id" db:"id"`
+	Exchange        string          `json:"exchange" db:"exchange"`
+	Source          string          `json:"source" db:"source"`
+	Symbol          string          `json:"symbol" db:"symbol"`
+	VenueSymbol     string          `json:"venue_symbol" db:"venue_symbol"`
+	Multiplier      float64         `json:"multiplier" db:"multiplier"`
+	Price           *float64        `json:"price,omitempty" db:"price"`
+	Rate            float64         `json:"rate" db:"rate"`
+	LongRate        *float64        `json:"long_rate,omitempty" db:"long_rate"`
+	ShortRate       *float64        `json:"short_rate,omitempty" db:"short_rate"`
+	LongBorrowRate  *float64        `json:"long_borrow_rate,omitempty" db:"long_borrow_rate"`
+	ShortBorrowRate *float64        `json:"short_borrow_rate,omitempty" db:"short_borrow_rate"`
+	FundingInterval time.Duration   `json:"funding_interval" db:"funding_interval"`
+	Timestamp       time.Time       `json:"timestamp" db:"timestamp"`
+	NextFunding     *time.Time      `json:"next_funding,omitempty" db:"next_funding"`
+	SampleType      SampleType      `json:"sample_type" db:"sample_type"`
+	RunID           *uuid.UUID      `json:"run_id,omitempty" db:"run_id"` // fetch run that collected the row, nil when the run could not be journaled
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	Market          *MarketSnapshot `json:"market,omitempty" db:"-"`
+	// Issues are parse failures reported by the adapter, rows with
+	// issues are quarantined instead of stored.
+	Issues []FieldIssue `json:"-" db:"-"`
+}
+
+// SampleType tells why a rate was sampled.
+type SampleType string
+
+const (
+	SampleScheduled SampleType = "scheduled"
+	// SamplePreSettlement is taken just before a funding settlement, for
+	// most venues it carries the rate that is actually paid.
+	SamplePreSettlement SampleType = "pre_settlement"
+	// SamplePostSettlement is taken just after a settlement and carries
+	// the first estimate of the new period.
+	SamplePostSettlement SampleType = "post_settlement"
+	SampleStream         SampleType = "stream"
+	// SampleSettled is a settled rate backfilled from the venue's history,
+	// stored once per market and settlement time.
+	SampleSettled SampleType = "settled"
+)
+
+// MarketSnapshot is the market context captured with a funding rate.
+// Open interest and volume are quote currency notionals.
+type MarketSnapshot struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	FundingRateID uuid.UUID `json:"funding_rate_id" db:"funding_rate_id"`
+	OpenInterest  *float64  `json:"open_interest,omitempty" db:"open_interest"`
+	Volume24h     *float64  `json:"volume_24h,omitempty" db:"volume_24h"`
+	MarkPrice     *float64  `json:"mark_price,omitempty" db:"mark_price"`
+	IndexPrice    *float64  `json:"index_price,omitempty" db:"index_price"`
+	// Premium is the mark to index premium the venue derives funding from.
+	Premium *float64 `json:"premium,omitempty" db:"premium"`
+}
+
+// FieldIssue describes a venue field that could not be used.
+type FieldIssue struct {
+	Field  string `json:"field"`
+	Raw    string `json:"raw"`
+	Reason string `json:"reason"`
+}
+
+// QuarantinedRate is a rejected row kept for inspection.
+type QuarantinedRate struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Exchange    string    `json:"exchange" db:"exchange"`
+	Source      string    `json:"source" db:"source"`
+	VenueSymbol string    `json:"venue_symbol" db:"venue_symbol"`
+	Field       string    `json:"field" db:"field"`
+	RawValue    string    `json:"raw_value" db:"raw_value"`
+	Reason      string    `json:"reason" db:"reason"`
+	Timestamp   time.Time `json:"timestamp" db:"timestamp"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// SymbolAlias maps a venue symbol to a canonical base asset. Exchange "*"
+// matches every venue.
+type SymbolAlias struct {
+	Exchange    string    `json:"exchange" db:"exchange"`
+	VenueSymbol string    `json:"venue_symbol" db:"venue_symbol"`
+	Symbol      string    `json:"symbol" db:"symbol"`
+	Multiplier  float64   `json:"multiplier" db:"multiplier"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// Lease is a named lock held by one tracker replica until it expires.
+type Lease struct {
+	Name       string    `json:"name" db:"name"`
+	Holder     string    `json:"holder" db:"holder"`
+	AcquiredAt time.Time `json:"acquired_at" db:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at" db:"renewed_at"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+	// Active is false once the lease expired without being released.
+	Active bool `json:"active" db:"-"`
+}
+
+// FetchRun is the journal entry of one fetch of one exchange. FinishedAt is
+// nil while the run is in progress, ErrorClass is empty when it succeeded.
+type FetchRun struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Exchange   string     `json:"exchange" db:"exchange"`
+	SampleType SampleType `json:"sample_type" db:"sample_type"`
+	StartedAt  time.Time  `json:"started_at" db:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	// Fetched rows were returned by the adapter, Stored and Rejected are
+	// the ones that passed and failed validation.
+	Fetched    int           `json:"fetched" db:"fetched"`
+	Stored     int           `json:"stored" db:"stored"`
+	Rejected   int           `json:"rejected" db:"rejected"`
+	ErrorClass RunErrorClass `json:"error_class,omitempty" db:"error_class"`
+	Error      string        `json:"error,omitempty" db:"error_message"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+}
+
+// Duration returns how long the run took, zero while it is in progress.
+func (r FetchRun) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return 0
+	}
+
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// RunErrorClass groups run failures by cause.
+type RunErrorClass string
+
+const (
+	// RunErrorPartial means some requests failed, the rows of the others
+	// were stored.
+	RunErrorPartial     RunErrorClass = "partial"
+	RunErrorEmpty       RunErrorClass = "empty"
+	RunErrorTimeout     RunErrorClass = "timeout"
+	RunErrorCanceled    RunErrorClass = "canceled"
+	RunErrorRateLimited RunErrorClass = "rate_limited"
+	RunErrorHTTPStatus  RunErrorClass = "http_status"
+	RunErrorNetwork     RunErrorClass = "network"
+	RunErrorDecode      RunErrorClass = "decode"
+	RunErrorStore       RunErrorClass = "store"
+	RunErrorOther       RunErrorClass = "other"
+)
+
+type FetchRunFilter struct {
+	Exchange   *string
+	SampleType *SampleType
+	ErrorClass *RunErrorClass
+	Failed     bool // only runs that finished with an error
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int
+	Offset     int
 }
 
 type FundingRateFilter struct {
 	Exchange  *string
-	Symbol    *string
-	Limit     int
-	Offset    int
-	SortBy    string // rate, timestamp, symbol
-	SortOrder string // asc, desc
+	Exchanges []string // restricts results to these exchanges when set
+	Source    *string
+	Sources   []string // restricts results to rows supplied by these adapters when set
+	// SampleType returns the latest sample of that type instead of the
+	// latest of any.
+	SampleType *SampleType
+	Symbol     *string
+	// MinOpenInterest and MinVolume24h drop markets below the thresholds,
+	// markets without a snapshot are dropped too.
+	MinOpenInterest *float64
+	MinVolume24h    *float64
+	Limit           int
+	Offset          int
+	SortBy          string // rate, timestamp, symbol
+	SortOrder       string // asc, desc
+}
+
+// IsSecondary reports whether the rate was supplied by another venue's feed.
+func (r FundingRate) IsSecondary() bool {
+	return r.Source != "" && r.Source != r.Exchange
+}
+
+// RateUnit is the period funding rates are normalized to.
+type RateUnit string
+
+const (
+	RatePerHour   RateUnit = "1h"
+	RatePer8Hours RateUnit = "8h"
+	RateAPR       RateUnit = "apr"
+)
+
+const hoursPerYear = 365 * 24
+
+// ParseRateUnit parses a rate unit, accepting a few common spellings.
+func ParseRateUnit(s string) (RateUnit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1h", "hour", "hourly", "per-hour":
+		return RatePerHour, nil
+	case "8h", "per-8h":
+		return RatePer8Hours, nil
+	case "apr", "year", "annual":
+		return RateAPR, nil
+	default:
+		return "", fmt.Errorf("unknown rate unit %q", s)
+	}
+}
+
+// Hours returns the length of the unit in hours.
+func (u RateUnit) Hours() float64 {
+	switch u {
+	case RatePer8Hours:
+		return 8
+	case RateAPR:
+		return hoursPerYear
+	default:
+		return 1
+	}
+}
+
+// RateIn converts the rate to the given unit. Rates without a known
+// interval are returned unchanged.
+func (r FundingRate) RateIn(unit RateUnit) float64 {
+	return r.convert(r.Rate, unit)
+}
+
+// SideRatesIn converts the per side funding and borrow rates to the given
+// unit, unset rates stay nil.
+func (r FundingRate) SideRatesIn(unit RateUnit) (long, short, longBorrow, shortBorrow *float64) {
+	convert := func(rate *float64) *float64 {
+		if rate == nil {
+			return nil
+		}
+		converted := r.convert(*rate, unit)
+		return &converted
+	}
+
+	return convert(r.LongRate), convert(r.ShortRate), convert(r.LongBorrowRate), convert(r.ShortBorrowRate)
+}
+
+func (r FundingRate) convert(rate float64, unit RateUnit) float64 {
+	if r.FundingInterval <= 0 {
+		return rate
+	}
+
+	return rate * unit.Hours() / r.FundingInterval.Hours()
 }
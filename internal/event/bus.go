@@ -0,0 +1,139 @@
+package event
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+const defaultBuffer = 64
+
+// Handler receives the events of one subscriber, one at a time.
+type Handler func(Event)
+
+// Bus fans events out to subscribers. Every subscriber has its own bounded
+// queue and goroutine, Publish never blocks: events for a full queue are
+// dropped and counted. A nil bus drops everything.
+type Bus struct {
+	logger *zap.Logger
+
+	mu          sync.RWMutex
+	subscribers []*subscriber
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+type subscriber struct {
+	name    string
+	types   []Type // empty for every type
+	queue   chan Event
+	dropped atomic.Int64
+}
+
+func NewBus(logger *zap.Logger) *Bus {
+	return &Bus{
+		logger: logger,
+	}
+}
+
+// Subscribe registers handler for the given event types, every type when
+// none is given. buffer bounds the queue and defaults to 64. Subscribers
+// are meant to be registered at startup, before anything is published.
+func (b *Bus) Subscribe(name string, buffer int, handler Handler, types ...Type) error {
+	if buffer <= 0 {
+		buffer = defaultBuffer
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return fmt.Errorf("subscribe %q: bus is closed", name)
+	}
+
+	sub := &subscriber{
+		name:  name,
+		types: types,
+		queue: make(chan Event, buffer),
+	}
+	b.subscribers = append(b.subscribers, sub)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for event := range sub.queue {
+			b.deliver(sub, handler, event)
+		}
+	}()
+
+	return nil
+}
+
+// Publish queues event for every subscriber of its type.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+
+	for _, sub := range b.subscribers {
+		if len(sub.types) > 0 && !slices.Contains(sub.types, event.Type()) {
+			continue
+		}
+
+		select {
+		case sub.queue <- event:
+		default:
+			b.logger.Warn("dropped event, subscriber is behind",
+				zap.String("subscriber", sub.name),
+				zap.String("event", string(event.Type())),
+				zap.Int64("dropped", sub.dropped.Add(1)),
+			)
+		}
+	}
+}
+
+// deliver calls handler, a panicking subscriber only loses the event.
+func (b *Bus) deliver(sub *subscriber, handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("event subscriber panicked",
+				zap.String("subscriber", sub.name),
+				zap.String("event", string(event.Type())),
+				zap.Any("panic", r),
+			)
+		}
+	}()
+
+	handler(event)
+}
+
+// Close stops accepting events and waits until the subscribers handled
+// the queued ones.
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, sub := range b.subscribers {
+		close(sub.queue)
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
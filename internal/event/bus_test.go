@@ -0,0 +1,178 @@
+package event
+
+import (
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newTestBus() (*Bus, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	return NewBus(zap.New(core)), logs
+}
+
+// record returns a handler appending the symbol of every listing event to
+// got, and the type of any other. got may be read after Close.
+func record(got *[]string) Handler {
+	return func(event Event) {
+		if listed, ok := event.(SymbolListed); ok {
+			*got = append(*got, listed.Symbol)
+			return
+		}
+		*got = append(*got, string(event.Type()))
+	}
+}
+
+func TestBusDropsEventsForFullQueue(t *testing.T) {
+	bus, logs := newTestBus()
+
+	var got []string
+	handling, release := make(chan struct{}), make(chan struct{})
+	err := bus.Subscribe("slow", 1, func(event Event) {
+		if len(got) == 0 {
+			close(handling)
+			<-release
+		}
+		record(&got)(event)
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	// the first is being handled, the second fills the queue
+	bus.Publish(SymbolListed{Symbol: "BTC"})
+	<-handling
+	bus.Publish(SymbolListed{Symbol: "ETH"})
+	bus.Publish(SymbolListed{Symbol: "SOL"})
+	bus.Publish(SymbolListed{Symbol: "DOGE"})
+
+	close(release)
+	bus.Close()
+
+	if want := []string{"BTC", "ETH"}; !slices.Equal(got, want) {
+		t.Errorf("handled %v, want %v", got, want)
+	}
+
+	drops := logs.FilterMessage("dropped event, subscriber is behind").AllUntimed()
+	if len(drops) != 2 {
+		t.Fatalf("logged %d drops, want 2", len(drops))
+	}
+	if dropped := drops[1].ContextMap()["dropped"]; dropped != int64(2) {
+		t.Errorf("dropped count = %v, want 2", dropped)
+	}
+}
+
+func TestBusFiltersByType(t *testing.T) {
+	bus, _ := newTestBus()
+
+	var listings, all []string
+	if err := bus.Subscribe("listings", 0, record(&listings), TypeSymbolListed, TypeSymbolDelisted); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := bus.Subscribe("all", 0, record(&all)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	bus.Publish(RatesCollected{})
+	bus.Publish(SymbolListed{Symbol: "BTC"})
+	bus.Publish(ExchangeFailed{})
+	bus.Publish(SymbolDelisted{})
+	bus.Close()
+
+	if want := []string{"BTC", "symbol_delisted"}; !slices.Equal(listings, want) {
+		t.Errorf("listings subscriber got %v, want %v", listings, want)
+	}
+	if want := []string{"rates_collected", "BTC", "exchange_failed", "symbol_delisted"}; !slices.Equal(all, want) {
+		t.Errorf("subscriber of every type got %v, want %v", all, want)
+	}
+}
+
+func TestBusRecoversFromPanickingSubscriber(t *testing.T) {
+	bus, logs := newTestBus()
+
+	var got, other []string
+	err := bus.Subscribe("panicky", 0, func(event Event) {
+		if listed, ok := event.(SymbolListed); ok && listed.Symbol == "BTC" {
+			panic("boom")
+		}
+		record(&got)(event)
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := bus.Subscribe("other", 0, record(&other)); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	bus.Publish(SymbolListed{Symbol: "BTC"})
+	bus.Publish(SymbolListed{Symbol: "ETH"})
+	bus.Close()
+
+	if want := []string{"ETH"}; !slices.Equal(got, want) {
+		t.Errorf("panicking subscriber handled %v, want %v", got, want)
+	}
+	if want := []string{"BTC", "ETH"}; !slices.Equal(other, want) {
+		t.Errorf("other subscriber handled %v, want %v", other, want)
+	}
+	if n := logs.FilterMessage("event subscriber panicked").Len(); n != 1 {
+		t.Errorf("panic logged %d times, want 1", n)
+	}
+}
+
+func TestBusCloseDrainsQueuedEvents(t *testing.T) {
+	bus, logs := newTestBus()
+
+	var got []string
+	release := make(chan struct{})
+	err := bus.Subscribe("blocked", 10, func(event Event) {
+		<-release
+		record(&got)(event)
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	symbols := []string{"BTC", "ETH", "SOL", "DOGE", "PEPE"}
+	for _, symbol := range symbols {
+		bus.Publish(SymbolListed{Symbol: symbol})
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		bus.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close returned before the queued events were handled")
+	default:
+	}
+	close(release)
+	<-closed
+
+	if !slices.Equal(got, symbols) {
+		t.Errorf("handled %v, want %v", got, symbols)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("logged %v, want nothing dropped", logs.AllUntimed())
+	}
+
+	// a closed bus ignores events and refuses subscribers
+	bus.Publish(SymbolListed{Symbol: "XRP"})
+	if err := bus.Subscribe("late", 0, func(Event) {}); err == nil {
+		t.Error("Subscribe on a closed bus succeeded")
+	}
+	bus.Close()
+	if !slices.Equal(got, symbols) {
+		t.Errorf("handled %v after close, want %v", got, symbols)
+	}
+}
+
+func TestNilBusDropsEverything(t *testing.T) {
+	var bus *Bus
+	bus.Publish(SymbolListed{Symbol: "BTC"})
+	bus.Close()
+}
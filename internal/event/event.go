@@ -0,0 +1,75 @@
+package event
+
+import (
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Type names an event, subscribers filter on it.
+type Type string
+
+const (
+	TypeRatesCollected Type = "rates_collected"
+	TypeExchangeFailed Type = "exchange_failed"
+	TypeSymbolListed   Type = "symbol_listed"
+	TypeSymbolDelisted Type = "symbol_delisted"
+)
+
+// Event is published by the tracker. Events are shared by all subscribers
+// and must not be modified.
+type Event interface {
+	Type() Type
+}
+
+// RatesCollected carries the rates of one adapter that passed validation
+// and were stored.
+type RatesCollected struct {
+	Source     string
+	SampleType domain.SampleType
+	RunID      *uuid.UUID // nil when the run could not be journaled
+	Rates      []domain.FundingRate
+	At         time.Time
+}
+
+func (RatesCollected) Type() Type { return TypeRatesCollected }
+
+// ExchangeFailed is published when a fetch returned nothing usable or its
+// rates could not be stored. Skips of an open circuit breaker are not
+// reported.
+type ExchangeFailed struct {
+	Exchange   string
+	SampleType domain.SampleType
+	RunID      *uuid.UUID
+	ErrorClass domain.RunErrorClass
+	Err        error
+	At         time.Time
+}
+
+func (ExchangeFailed) Type() Type { return TypeExchangeFailed }
+
+// SymbolListed is published when a symbol shows up that the previous fetch
+// of the same adapter did not return. The first fetch after startup only
+// records the listed symbols.
+type SymbolListed struct {
+	Source      string
+	Exchange    string
+	Symbol      string
+	VenueSymbol string
+	At          time.Time
+}
+
+func (SymbolListed) Type() Type { return TypeSymbolListed }
+
+// SymbolDelisted is published when a complete fetch no longer returns a
+// symbol the previous one did.
+type SymbolDelisted struct {
+	Source      string
+	Exchange    string
+	Symbol      string
+	VenueSymbol string
+	At          time.Time
+}
+
+func (SymbolDelisted) Type() Type { return TypeSymbolDelisted }
@@ -0,0 +1,19 @@
+// Package all registers every in-tree exchange adapter.
+package all
+
+import (
+	_ "github.com/fiensola/funding/internal/exchange/backpack"
+	_ "github.com/fiensola/funding/internal/exchange/binance"
+	_ "github.com/fiensola/funding/internal/exchange/bybit"
+	_ "github.com/fiensola/funding/internal/exchange/dydx"
+	_ "github.com/fiensola/funding/internal/exchange/extended"
+	_ "github.com/fiensola/funding/internal/exchange/gmx"
+	_ "github.com/fiensola/funding/internal/exchange/hibachi"
+	_ "github.com/fiensola/funding/internal/exchange/hyperliquid"
+	_ "github.com/fiensola/funding/internal/exchange/jsonrest"
+	_ "github.com/fiensola/funding/internal/exchange/lighter"
+	_ "github.com/fiensola/funding/internal/exchange/okx"
+	_ "github.com/fiensola/funding/internal/exchange/pacifica"
+	_ "github.com/fiensola/funding/internal/exchange/plugin"
+	_ "github.com/fiensola/funding/internal/exchange/synthetic"
+)
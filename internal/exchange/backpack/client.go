@@ -2,11 +2,7 @@ package backpack
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/fiensola/funding/internal/domain"
@@ -14,75 +10,93 @@ import (
 	"go.uber.org/zap"
 )
 
+// fundingInterval is the settlement period of backpack funding.
+const fundingInterval = 8 * time.Hour
+
 type Client struct {
 	config     exchange.Config
-	httpClient *http.Client
+	httpClient *exchange.HTTPClient
 	logger     *zap.Logger
 }
 
-func NewClient(config exchange.Config, logger *zap.Logger) *Client {
-	transport := &http.Transport{}
-
-	httpClient := &http.Client{
-		Timeout:   5 * time.Second,
-		Transport: transport,
-	}
+func init() {
+	exchange.Register("backpack", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		if _, ok := config.Options["ws_url"]; ok {
+			return NewStreamClient(config, logger)
+		}
+		return NewClient(config, logger), nil
+	})
+}
 
+func NewClient(config exchange.Config, logger *zap.Logger) *Client {
 	return &Client{
 		config:     config,
-		httpClient: httpClient,
+		httpClient: exchange.NewHTTPClient(config, logger),
 		logger:     logger,
 	}
 }
 
+// Name returns the name of the config block, rows are stored under it.
 func (c *Client) Name() string {
-	return "backpack"
+	return c.config.Name
 }
 
 type fundingResponse []struct {
-	Rate   string `json:"fundingRate"`
-	Symbol string `json:"symbol"`
-	Price  string `json:"markPrice"`
+	Rate        string `json:"fundingRate"`
+	Symbol      string `json:"symbol"`
+	Price       string `json:"markPrice"`
+	IndexPrice  string `json:"indexPrice"`
+	NextFunding int64  `json:"nextFundingTimestamp"`
 }
 
-func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
-	url := fmt.Sprintf("%s/v1/markPrices", c.config.BaseURL)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
+type openInterestResponse []struct {
+	Symbol       string `json:"symbol"`
+	OpenInterest string `json:"openInterest"` // base currency
+}
 
-	req.Header.Set("Content-Type", "application/json")
+type tickersResponse []struct {
+	Symbol      string `json:"symbol"`
+	QuoteVolume string `json:"quoteVolume"`
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
-	}
-	defer resp.Body.Close()
+type marketStats struct {
+	openInterest *float64
+	volume       *float64
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	url := fmt.Sprintf("%s/v1/markPrices", c.config.BaseURL)
 
 	var fundingResp fundingResponse
-	if err := json.NewDecoder(resp.Body).Decode(&fundingResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := c.httpClient.GetJSON(ctx, url, &fundingResp); err != nil {
+		return nil, err
 	}
 
+	stats := c.getMarketStats(ctx)
+
 	now := time.Now()
 	rates := make([]domain.FundingRate, 0)
 
+	var parser exchange.FieldParser
 	for _, item := range fundingResp {
-		rate, _ := strconv.ParseFloat(item.Rate, 64)
-		price, _ := strconv.ParseFloat(item.Price, 64)
-		symbolWords := strings.Split(item.Symbol, "_")
+		rate := parser.Float("fundingRate", item.Rate)
+		price := parser.Float("markPrice", item.Price)
+		index := parser.OptionalFloat("indexPrice", item.IndexPrice)
+		stat := stats[item.Symbol]
+		nextFunding := exchange.UnixMilli(item.NextFunding)
+		if nextFunding == nil {
+			nextFunding = exchange.NextSettlement(now, fundingInterval)
+		}
 		rates = append(rates, domain.FundingRate{
-			Exchange:  c.Name(),
-			Symbol:    symbolWords[0],
-			Rate:      rate,
-			Price:     &price,
-			Timestamp: now,
+			Exchange:        c.Name(),
+			VenueSymbol:     item.Symbol,
+			Rate:            rate,
+			FundingInterval: fundingInterval,
+			Price:           &price,
+			Timestamp:       now,
+			NextFunding:     nextFunding,
+			Market:          exchange.Snapshot(exchange.Notional(stat.openInterest, &price), stat.volume, &price, index),
+			Issues:          parser.Issues(),
 		})
 	}
 
@@ -93,3 +107,40 @@ func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, e
 
 	return rates, nil
 }
+
+// getMarketStats returns open interest and 24h volume per symbol. They only
+// add context, so failures are logged and the rates are stored without them.
+func (c *Client) getMarketStats(ctx context.Context) map[string]marketStats {
+	stats := make(map[string]marketStats)
+
+	var oiResp openInterestResponse
+	if err := c.httpClient.GetJSON(ctx, fmt.Sprintf("%s/v1/openInterest", c.config.BaseURL), &oiResp); err != nil {
+		c.logger.Warn("failed to fetch open interest", zap.String("exchange", c.Name()), zap.Error(err))
+	}
+
+	var tickersResp tickersResponse
+	if err := c.httpClient.GetJSON(ctx, fmt.Sprintf("%s/v1/tickers", c.config.BaseURL), &tickersResp); err != nil {
+		c.logger.Warn("failed to fetch tickers", zap.String("exchange", c.Name()), zap.Error(err))
+	}
+
+	var parser exchange.FieldParser
+	for _, item := range oiResp {
+		stat := stats[item.Symbol]
+		stat.openInterest = parser.OptionalFloat("openInterest", item.OpenInterest)
+		stats[item.Symbol] = stat
+	}
+	for _, item := range tickersResp {
+		stat := stats[item.Symbol]
+		stat.volume = parser.OptionalFloat("quoteVolume", item.QuoteVolume)
+		stats[item.Symbol] = stat
+	}
+
+	if issues := parser.Issues(); len(issues) > 0 {
+		c.logger.Warn("ignored malformed market stats",
+			zap.String("exchange", c.Name()),
+			zap.Int("count", len(issues)),
+		)
+	}
+
+	return stats
+}
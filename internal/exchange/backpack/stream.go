@@ -0,0 +1,121 @@
+package backpack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+type streamOptions struct {
+	WSURL        string        `mapstructure:"ws_url"`
+	PingInterval time.Duration `mapstructure:"ping_interval"`
+}
+
+// StreamClient is a Client that also pushes mark price updates, which carry
+// the current funding rate, from the backpack websocket.
+type StreamClient struct {
+	*Client
+	options streamOptions
+}
+
+func NewStreamClient(config exchange.Config, logger *zap.Logger) (*StreamClient, error) {
+	var options streamOptions
+	if err := config.DecodeOptions(&options); err != nil {
+		return nil, err
+	}
+	if options.WSURL == "" {
+		return nil, fmt.Errorf("ws_url is required")
+	}
+
+	return &StreamClient{
+		Client:  NewClient(config, logger),
+		options: options,
+	}, nil
+}
+
+type streamMessage struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		Event  string `json:"e"`
+		Symbol string `json:"s"`
+		Price  string `json:"p"`
+		Rate   string `json:"f"`
+		// NextFunding is in microseconds like every websocket timestamp
+		NextFunding int64 `json:"n"`
+	} `json:"data"`
+}
+
+func (c *StreamClient) Stream(ctx context.Context, out chan<- domain.FundingRate) error {
+	return exchange.RunWebSocket(ctx, exchange.WSConfig{
+		URL:          c.options.WSURL,
+		Proxy:        c.config.Proxy,
+		PingInterval: c.options.PingInterval,
+		Subscribe:    c.subscriptions,
+		Handle: func(msg []byte) error {
+			var streamMsg streamMessage
+			if err := json.Unmarshal(msg, &streamMsg); err != nil {
+				return fmt.Errorf("decode message: %w", err)
+			}
+
+			if streamMsg.Data.Event != "markPrice" {
+				return nil
+			}
+
+			var parser exchange.FieldParser
+			rate := parser.Float("f", streamMsg.Data.Rate)
+			price := parser.Float("p", streamMsg.Data.Price)
+
+			select {
+			case out <- domain.FundingRate{
+				Exchange:        c.Name(),
+				VenueSymbol:     streamMsg.Data.Symbol,
+				Rate:            rate,
+				FundingInterval: fundingInterval,
+				Price:           &price,
+				Timestamp:       time.Now(),
+				NextFunding:     unixMicro(streamMsg.Data.NextFunding),
+				Issues:          parser.Issues(),
+			}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}, c.logger)
+}
+
+// subscriptions builds one mark price subscription per perp market, it runs
+// on every reconnect so newly listed markets are picked up.
+func (c *StreamClient) subscriptions(ctx context.Context) ([]any, error) {
+	rates, err := c.FetchFundingRates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list markets: %w", err)
+	}
+
+	params := make([]string, 0, len(rates))
+	for _, rate := range rates {
+		params = append(params, "markPrice."+rate.VenueSymbol)
+	}
+
+	return []any{
+		map[string]any{
+			"method": "SUBSCRIBE",
+			"params": params,
+		},
+	}, nil
+}
+
+func unixMicro(us int64) *time.Time {
+	if us <= 0 {
+		return nil
+	}
+
+	t := time.UnixMicro(us).UTC()
+
+	return &t
+}
@@ -0,0 +1,165 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+// fundingInterval is the default settlement period of binance funding,
+// symbols listed in fundingInfo override it.
+const fundingInterval = 8 * time.Hour
+
+type Client struct {
+	config     exchange.Config
+	httpClient *exchange.HTTPClient
+	logger     *zap.Logger
+}
+
+func init() {
+	exchange.Register("binance", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger), nil
+	})
+}
+
+func NewClient(config exchange.Config, logger *zap.Logger) *Client {
+	return &Client{
+		config:     config,
+		httpClient: exchange.NewHTTPClient(config, logger),
+		logger:     logger,
+	}
+}
+
+// Name returns the name of the config block, rows are stored under it.
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+type premiumIndexResponse []struct {
+	Symbol      string `json:"symbol"`
+	MarkPrice   string `json:"markPrice"`
+	IndexPrice  string `json:"indexPrice"`
+	Rate        string `json:"lastFundingRate"`
+	NextFunding int64  `json:"nextFundingTime"`
+}
+
+type fundingInfoResponse []struct {
+	Symbol        string `json:"symbol"`
+	IntervalHours int    `json:"fundingIntervalHours"`
+}
+
+type tickersResponse []struct {
+	Symbol      string `json:"symbol"`
+	QuoteVolume string `json:"quoteVolume"`
+}
+
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	url := fmt.Sprintf("%s/fapi/v1/premiumIndex", c.config.BaseURL)
+
+	var premiumResp premiumIndexResponse
+	if err := c.httpClient.GetJSON(ctx, url, &premiumResp); err != nil {
+		return nil, err
+	}
+
+	intervals, err := c.getIntervals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := c.getVolumes(ctx)
+
+	now := time.Now()
+	rates := make([]domain.FundingRate, 0)
+
+	var parser exchange.FieldParser
+	for _, item := range premiumResp {
+		// delivery contracts (BTCUSDT_250328) share the endpoint but do
+		// not pay funding
+		if strings.Contains(item.Symbol, "_") {
+			continue
+		}
+
+		interval := fundingInterval
+		if hours, ok := intervals[item.Symbol]; ok {
+			interval = hours
+		}
+
+		rate := parser.Float("lastFundingRate", item.Rate)
+		price := parser.Float("markPrice", item.MarkPrice)
+		index := parser.OptionalFloat("indexPrice", item.IndexPrice)
+		nextFunding := exchange.UnixMilli(item.NextFunding)
+		if nextFunding == nil {
+			nextFunding = exchange.NextSettlement(now, interval)
+		}
+		rates = append(rates, domain.FundingRate{
+			Exchange:        c.Name(),
+			VenueSymbol:     item.Symbol,
+			Rate:            rate,
+			FundingInterval: interval,
+			Price:           &price,
+			Timestamp:       now,
+			NextFunding:     nextFunding,
+			Market:          exchange.Snapshot(nil, volumes[item.Symbol], &price, index),
+			Issues:          parser.Issues(),
+		})
+	}
+
+	c.logger.Info("fetched funding rates",
+		zap.String("exchange", c.Name()),
+		zap.Int("count", len(rates)),
+	)
+
+	return rates, nil
+}
+
+// getIntervals returns the funding interval of symbols that do not settle
+// every 8h. A wrong interval skews every normalized rate, so unlike the
+// market stats a failure here fails the fetch.
+func (c *Client) getIntervals(ctx context.Context) (map[string]time.Duration, error) {
+	url := fmt.Sprintf("%s/fapi/v1/fundingInfo", c.config.BaseURL)
+
+	var infoResp fundingInfoResponse
+	if err := c.httpClient.GetJSON(ctx, url, &infoResp); err != nil {
+		return nil, err
+	}
+
+	intervals := make(map[string]time.Duration, len(infoResp))
+	for _, item := range infoResp {
+		if item.IntervalHours > 0 {
+			intervals[item.Symbol] = time.Duration(item.IntervalHours) * time.Hour
+		}
+	}
+
+	return intervals, nil
+}
+
+// getVolumes returns the 24h quote volume per symbol. It only adds context,
+// so failures are logged and the rates are stored without it.
+func (c *Client) getVolumes(ctx context.Context) map[string]*float64 {
+	volumes := make(map[string]*float64)
+
+	var tickersResp tickersResponse
+	if err := c.httpClient.GetJSON(ctx, fmt.Sprintf("%s/fapi/v1/ticker/24hr", c.config.BaseURL), &tickersResp); err != nil {
+		c.logger.Warn("failed to fetch tickers", zap.String("exchange", c.Name()), zap.Error(err))
+		return volumes
+	}
+
+	var parser exchange.FieldParser
+	for _, item := range tickersResp {
+		volumes[item.Symbol] = parser.OptionalFloat("quoteVolume", item.QuoteVolume)
+	}
+
+	if issues := parser.Issues(); len(issues) > 0 {
+		c.logger.Warn("ignored malformed market stats",
+			zap.String("exchange", c.Name()),
+			zap.Int("count", len(issues)),
+		)
+	}
+
+	return volumes
+}
@@ -0,0 +1,212 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// BreakerConfig controls when a breaker opens and how it recovers. Zero
+// values fall back to the defaults below.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens
+	// the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before letting a
+	// trial request through.
+	OpenTimeout time.Duration
+	// HalfOpenSuccesses is the number of successful trial requests needed
+	// to close the breaker again.
+	HalfOpenSuccesses int
+}
+
+const (
+	defaultFailureThreshold  = 3
+	defaultOpenTimeout       = time.Minute
+	defaultHalfOpenSuccesses = 1
+)
+
+func (c BreakerConfig) withDefaults() BreakerConfig {
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = defaultFailureThreshold
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = defaultOpenTimeout
+	}
+	if c.HalfOpenSuccesses <= 0 {
+		c.HalfOpenSuccesses = defaultHalfOpenSuccesses
+	}
+
+	return c
+}
+
+// Health is a point in time view of an exchange's breaker.
+type Health struct {
+	Exchange            string       `json:"exchange"`
+	State               BreakerState `json:"state"`
+	LastSuccess         *time.Time   `json:"last_success,omitempty"`
+	LastError           string       `json:"last_error,omitempty"`
+	LastErrorAt         *time.Time   `json:"last_error_at,omitempty"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+}
+
+// CircuitBreaker wraps an exchange and stops calling it after repeated
+// failures until OpenTimeout has passed.
+type CircuitBreaker struct {
+	Exchange
+	config BreakerConfig
+
+	mu          sync.Mutex
+	state       BreakerState
+	failures    int
+	successes   int
+	trial       bool
+	openedAt    time.Time
+	lastSuccess time.Time
+	lastError   error
+	lastErrorAt time.Time
+	onChange    func(from, to BreakerState)
+	now         func() time.Time
+}
+
+func NewCircuitBreaker(ex Exchange, config BreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		Exchange: ex,
+		config:   config.withDefaults(),
+		state:    BreakerClosed,
+		now:      time.Now,
+	}
+}
+
+// OnStateChange registers a callback invoked on every state transition.
+// It must be set before the breaker is used.
+func (b *CircuitBreaker) OnStateChange(fn func(from, to BreakerState)) {
+	b.onChange = fn
+}
+
+func (b *CircuitBreaker) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	rates, err := b.Exchange.FetchFundingRates(ctx)
+	b.record(err)
+
+	return rates, err
+}
+
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.config.OpenTimeout {
+			return ErrBreakerOpen
+		}
+		b.setState(BreakerHalfOpen)
+		b.successes = 0
+	case BreakerHalfOpen:
+		// only one trial request at a time
+		if b.trial {
+			return ErrBreakerOpen
+		}
+	}
+
+	if b.state == BreakerHalfOpen {
+		b.trial = true
+	}
+
+	return nil
+}
+
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	now := b.now()
+
+	// a partial result means the exchange is up
+	var partial *PartialError
+	if errors.As(err, &partial) && !partial.AllFailed() {
+		err = nil
+	}
+
+	if err != nil {
+		// the caller gave up, that says nothing about the exchange
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+
+		b.failures++
+		b.lastError = err
+		b.lastErrorAt = now
+
+		if b.state == BreakerHalfOpen || b.failures >= b.config.FailureThreshold {
+			b.openedAt = now
+			b.setState(BreakerOpen)
+		}
+		return
+	}
+
+	b.failures = 0
+	b.lastSuccess = now
+
+	if b.state == BreakerHalfOpen {
+		b.successes++
+		if b.successes >= b.config.HalfOpenSuccesses {
+			b.setState(BreakerClosed)
+		}
+	}
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	if b.state == state {
+		return
+	}
+
+	from := b.state
+	b.state = state
+
+	if b.onChange != nil {
+		b.onChange(from, state)
+	}
+}
+
+func (b *CircuitBreaker) Health() Health {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	health := Health{
+		Exchange:            b.Name(),
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+
+	if !b.lastSuccess.IsZero() {
+		lastSuccess := b.lastSuccess
+		health.LastSuccess = &lastSuccess
+	}
+
+	if b.lastError != nil {
+		lastErrorAt := b.lastErrorAt
+		health.LastError = b.lastError.Error()
+		health.LastErrorAt = &lastErrorAt
+	}
+
+	return health
+}
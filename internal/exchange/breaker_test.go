@@ -0,0 +1,186 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+// fakeExchange answers every fetch with fetch.
+type fakeExchange struct {
+	fetch func(ctx context.Context) ([]domain.FundingRate, error)
+}
+
+func (f *fakeExchange) Name() string {
+	return "fake"
+}
+
+func (f *fakeExchange) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	return f.fetch(ctx)
+}
+
+// newTestBreaker returns a breaker over a fake that fails with the returned
+// error while it is set, a func advancing its clock and its transitions.
+func newTestBreaker(config BreakerConfig) (*CircuitBreaker, *error, func(time.Duration), *[]string) {
+	var fail error
+	ex := &fakeExchange{fetch: func(ctx context.Context) ([]domain.FundingRate, error) {
+		return nil, fail
+	}}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(ex, config)
+	breaker.now = func() time.Time { return now }
+
+	var transitions []string
+	breaker.OnStateChange(func(from, to BreakerState) {
+		transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+	})
+
+	return breaker, &fail, func(d time.Duration) { now = now.Add(d) }, &transitions
+}
+
+func TestCircuitBreakerStateMachine(t *testing.T) {
+	breaker, fail, advance, transitions := newTestBreaker(BreakerConfig{
+		FailureThreshold:  2,
+		OpenTimeout:       time.Minute,
+		HalfOpenSuccesses: 2,
+	})
+	ctx := context.Background()
+	down := errors.New("connection refused")
+
+	fetch := func(want error) {
+		t.Helper()
+		if _, err := breaker.FetchFundingRates(ctx); !errors.Is(err, want) {
+			t.Fatalf("fetch err = %v, want %v", err, want)
+		}
+	}
+	state := func(want BreakerState) {
+		t.Helper()
+		if got := breaker.Health().State; got != want {
+			t.Fatalf("state = %s, want %s", got, want)
+		}
+	}
+
+	*fail = down
+	fetch(down)
+	state(BreakerClosed)
+	fetch(down)
+	state(BreakerOpen)
+
+	// open until the timeout passed
+	fetch(ErrBreakerOpen)
+	advance(time.Minute - time.Second)
+	fetch(ErrBreakerOpen)
+
+	// a failed trial opens it again for another timeout
+	advance(time.Second)
+	fetch(down)
+	state(BreakerOpen)
+	advance(30 * time.Second)
+	fetch(ErrBreakerOpen)
+
+	// two successful trials close it
+	advance(30 * time.Second)
+	*fail = nil
+	fetch(nil)
+	state(BreakerHalfOpen)
+	fetch(nil)
+	state(BreakerClosed)
+
+	want := []string{
+		"closed->open",
+		"open->half_open", "half_open->open",
+		"open->half_open", "half_open->closed",
+	}
+	if fmt.Sprint(*transitions) != fmt.Sprint(want) {
+		t.Errorf("transitions = %v, want %v", *transitions, want)
+	}
+
+	health := breaker.Health()
+	if health.ConsecutiveFailures != 0 || health.LastSuccess == nil || health.LastError != down.Error() {
+		t.Errorf("health = %+v, want no failures with the last success and error", health)
+	}
+}
+
+func TestCircuitBreakerLetsOneTrialThrough(t *testing.T) {
+	breaker, fail, advance, _ := newTestBreaker(BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+	ctx := context.Background()
+
+	*fail = errors.New("connection refused")
+	breaker.FetchFundingRates(ctx)
+	advance(time.Minute)
+
+	// the trial is still running while the others arrive
+	started, release := make(chan struct{}), make(chan struct{})
+	breaker.Exchange = &fakeExchange{fetch: func(ctx context.Context) ([]domain.FundingRate, error) {
+		close(started)
+		<-release
+		return nil, nil
+	}}
+	done := make(chan error)
+	go func() {
+		_, err := breaker.FetchFundingRates(ctx)
+		done <- err
+	}()
+	<-started
+
+	for i := 0; i < 3; i++ {
+		if _, err := breaker.FetchFundingRates(ctx); !errors.Is(err, ErrBreakerOpen) {
+			t.Errorf("fetch during the trial: err = %v, want ErrBreakerOpen", err)
+		}
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("trial err = %v", err)
+	}
+	if state := breaker.Health().State; state != BreakerClosed {
+		t.Errorf("state after the trial = %s, want closed", state)
+	}
+}
+
+func TestCircuitBreakerFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		open bool
+	}{
+		{
+			name: "failure",
+			err:  errors.New("connection refused"),
+			open: true,
+		},
+		{
+			name: "partial result means the exchange is up",
+			err:  &PartialError{Exchange: "fake", Total: 2, Failures: map[string]error{"BTC": errors.New("timeout")}},
+		},
+		{
+			name: "partial result where every request failed",
+			err:  &PartialError{Exchange: "fake", Total: 1, Failures: map[string]error{"BTC": errors.New("timeout")}},
+			open: true,
+		},
+		{
+			name: "canceled by the caller",
+			err:  fmt.Errorf("fetch: %w", context.Canceled),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker, fail, _, _ := newTestBreaker(BreakerConfig{FailureThreshold: 2})
+
+			*fail = tt.err
+			for i := 0; i < 2; i++ {
+				breaker.FetchFundingRates(context.Background())
+			}
+
+			if open := breaker.Health().State == BreakerOpen; open != tt.open {
+				t.Errorf("open = %v after two fetches, want %v", open, tt.open)
+			}
+		})
+	}
+}
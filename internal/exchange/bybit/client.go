@@ -0,0 +1,243 @@
+package bybit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+// fundingInterval is the default settlement period of bybit funding,
+// instruments-info reports the actual one per contract.
+const fundingInterval = 8 * time.Hour
+
+// instrumentsPageSize is the largest page instruments-info serves.
+const instrumentsPageSize = 1000
+
+type Client struct {
+	config     exchange.Config
+	options    options
+	httpClient *exchange.HTTPClient
+	logger     *zap.Logger
+
+	instrumentsMu sync.Mutex
+	instruments   map[string]instrument
+	fetchedAt     time.Time
+}
+
+type options struct {
+	// InstrumentsTTL is how long contract metadata is reused.
+	InstrumentsTTL time.Duration `mapstructure:"instruments_ttl"`
+}
+
+type instrument struct {
+	interval time.Duration
+}
+
+func init() {
+	exchange.Register("bybit", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger)
+	})
+}
+
+func NewClient(config exchange.Config, logger *zap.Logger) (*Client, error) {
+	options := options{
+		InstrumentsTTL: time.Hour,
+	}
+	if err := config.DecodeOptions(&options); err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		config:     config,
+		options:    options,
+		httpClient: exchange.NewHTTPClient(config, logger),
+		logger:     logger,
+	}, nil
+}
+
+// Name returns the name of the config block, rows are stored under it.
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+// bybit reports api errors with a 200 status and a non zero retCode
+type envelope struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+}
+
+func (e envelope) err() error {
+	if e.RetCode != 0 {
+		return fmt.Errorf("unexpected response code %d: %s", e.RetCode, e.RetMsg)
+	}
+
+	return nil
+}
+
+type tickersResponse struct {
+	envelope
+	Result struct {
+		List []struct {
+			Symbol       string `json:"symbol"`
+			Rate         string `json:"fundingRate"`
+			NextFunding  string `json:"nextFundingTime"` // ms timestamp
+			MarkPrice    string `json:"markPrice"`
+			IndexPrice   string `json:"indexPrice"`
+			OpenInterest string `json:"openInterestValue"` // quote currency
+			Volume       string `json:"turnover24h"`
+		} `json:"list"`
+	} `json:"result"`
+}
+
+type instrumentsResponse struct {
+	envelope
+	Result struct {
+		List []struct {
+			Symbol          string `json:"symbol"`
+			Status          string `json:"status"`
+			ContractType    string `json:"contractType"`
+			FundingInterval int    `json:"fundingInterval"` // minutes
+		} `json:"list"`
+		NextPageCursor string `json:"nextPageCursor"`
+	} `json:"result"`
+}
+
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	instruments, err := c.getInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tickersUrl := fmt.Sprintf("%s/v5/market/tickers?category=linear", c.config.BaseURL)
+
+	var tickersResp tickersResponse
+	if err := c.httpClient.GetJSON(ctx, tickersUrl, &tickersResp); err != nil {
+		return nil, err
+	}
+	if err := tickersResp.err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rates := make([]domain.FundingRate, 0)
+
+	var parser exchange.FieldParser
+	for _, item := range tickersResp.Result.List {
+		// dated futures, pre-launch and delisted contracts
+		instrument, ok := instruments[item.Symbol]
+		if !ok {
+			continue
+		}
+
+		rate := parser.Float("fundingRate", item.Rate)
+		price := parser.Float("markPrice", item.MarkPrice)
+		index := parser.OptionalFloat("indexPrice", item.IndexPrice)
+		openInterest := parser.OptionalFloat("openInterestValue", item.OpenInterest)
+		volume := parser.OptionalFloat("turnover24h", item.Volume)
+
+		var nextFunding *time.Time
+		if ts, err := strconv.ParseInt(item.NextFunding, 10, 64); err == nil {
+			nextFunding = exchange.UnixMilli(ts)
+		}
+		if nextFunding == nil {
+			nextFunding = exchange.NextSettlement(now, instrument.interval)
+		}
+
+		rates = append(rates, domain.FundingRate{
+			Exchange:        c.Name(),
+			VenueSymbol:     item.Symbol,
+			Rate:            rate,
+			FundingInterval: instrument.interval,
+			Price:           &price,
+			Timestamp:       now,
+			NextFunding:     nextFunding,
+			Market:          exchange.Snapshot(openInterest, volume, &price, index),
+			Issues:          parser.Issues(),
+		})
+	}
+
+	c.logger.Info("fetched funding rates",
+		zap.String("exchange", c.Name()),
+		zap.Int("count", len(rates)),
+	)
+
+	return rates, nil
+}
+
+// getInstruments returns the trading perpetual contracts by symbol. The list
+// rarely changes and takes several pages, so it is cached for
+// InstrumentsTTL. A failed refresh keeps serving the previous list.
+func (c *Client) getInstruments(ctx context.Context) (map[string]instrument, error) {
+	c.instrumentsMu.Lock()
+	defer c.instrumentsMu.Unlock()
+
+	if c.instruments != nil && time.Since(c.fetchedAt) < c.options.InstrumentsTTL {
+		return c.instruments, nil
+	}
+
+	instruments, err := c.fetchInstruments(ctx)
+	if err != nil {
+		if c.instruments != nil {
+			c.logger.Warn("failed to refresh instruments, using cached",
+				zap.String("exchange", c.Name()),
+				zap.Error(err),
+			)
+			return c.instruments, nil
+		}
+		return nil, err
+	}
+
+	c.instruments = instruments
+	c.fetchedAt = time.Now()
+
+	return instruments, nil
+}
+
+func (c *Client) fetchInstruments(ctx context.Context) (map[string]instrument, error) {
+	instruments := make(map[string]instrument)
+
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("category", "linear")
+		query.Set("limit", strconv.Itoa(instrumentsPageSize))
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		pageUrl := fmt.Sprintf("%s/v5/market/instruments-info?%s", c.config.BaseURL, query.Encode())
+
+		var instrumentsResp instrumentsResponse
+		if err := c.httpClient.GetJSON(ctx, pageUrl, &instrumentsResp); err != nil {
+			return nil, err
+		}
+		if err := instrumentsResp.err(); err != nil {
+			return nil, err
+		}
+
+		for _, item := range instrumentsResp.Result.List {
+			if item.ContractType != "LinearPerpetual" || item.Status != "Trading" {
+				continue
+			}
+
+			interval := fundingInterval
+			if item.FundingInterval > 0 {
+				interval = time.Duration(item.FundingInterval) * time.Minute
+			}
+			instruments[item.Symbol] = instrument{interval: interval}
+		}
+
+		cursor = instrumentsResp.Result.NextPageCursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	return instruments, nil
+}
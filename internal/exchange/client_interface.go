@@ -2,8 +2,11 @@ package exchange
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/fiensola/funding/internal/domain"
+	"github.com/go-viper/mapstructure/v2"
 )
 
 type Exchange interface {
@@ -11,8 +14,43 @@ type Exchange interface {
 	FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error)
 }
 
+// HistoryExchange is implemented by exchanges that can backfill settled
+// funding.
+type HistoryExchange interface {
+	Exchange
+	FetchFundingHistory(ctx context.Context, symbol string, from time.Time) ([]domain.FundingRate, error)
+}
+
 type Config struct {
+	// Name is the key of the exchange config block, it differs from the
+	// adapter name for generic adapters.
+	Name     string
 	BaseURL  string
 	Proxy    string
 	IsActive bool
+	HTTP     HTTPConfig
+	// Options holds adapter specific settings from the exchange config block.
+	Options map[string]any
+}
+
+// DecodeOptions decodes adapter specific options into out.
+func (c Config) DecodeOptions(out any) error {
+	if len(c.Options) == 0 {
+		return nil
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           out,
+		WeaklyTypedInput: true,
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+	})
+	if err != nil {
+		return fmt.Errorf("create options decoder: %w", err)
+	}
+
+	if err := decoder.Decode(c.Options); err != nil {
+		return fmt.Errorf("decode options: %w", err)
+	}
+
+	return nil
 }
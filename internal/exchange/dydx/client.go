@@ -0,0 +1,167 @@
+package dydx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+// fundingInterval is the settlement period of dydx funding.
+const fundingInterval = time.Hour
+
+// historyPageSize is the largest page the indexer serves.
+const historyPageSize = 100
+
+type Client struct {
+	config     exchange.Config
+	httpClient *exchange.HTTPClient
+	logger     *zap.Logger
+}
+
+func init() {
+	exchange.Register("dydx", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger), nil
+	})
+}
+
+func NewClient(config exchange.Config, logger *zap.Logger) *Client {
+	return &Client{
+		config:     config,
+		httpClient: exchange.NewHTTPClient(config, logger),
+		logger:     logger,
+	}
+}
+
+// Name returns the name of the config block, rows are stored under it.
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+type marketsResponse struct {
+	Markets map[string]struct {
+		Ticker       string `json:"ticker"`
+		Status       string `json:"status"`
+		OraclePrice  string `json:"oraclePrice"`
+		Rate         string `json:"nextFundingRate"`
+		OpenInterest string `json:"openInterest"` // base currency
+		Volume       string `json:"volume24H"`
+	} `json:"markets"`
+}
+
+type historyResponse struct {
+	HistoricalFunding []struct {
+		Ticker            string    `json:"ticker"`
+		Rate              string    `json:"rate"`
+		Price             string    `json:"price"`
+		EffectiveAt       time.Time `json:"effectiveAt"`
+		EffectiveAtHeight string    `json:"effectiveAtHeight"`
+	} `json:"historicalFunding"`
+}
+
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	marketsUrl := fmt.Sprintf("%s/v4/perpetualMarkets", c.config.BaseURL)
+
+	var marketsResp marketsResponse
+	if err := c.httpClient.GetJSON(ctx, marketsUrl, &marketsResp); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rates := make([]domain.FundingRate, 0, len(marketsResp.Markets))
+
+	var parser exchange.FieldParser
+	for _, item := range marketsResp.Markets {
+		if item.Status != "ACTIVE" {
+			continue
+		}
+
+		rate := parser.Float("nextFundingRate", item.Rate)
+		price := parser.Float("oraclePrice", item.OraclePrice)
+		openInterest := parser.OptionalFloat("openInterest", item.OpenInterest)
+		volume := parser.OptionalFloat("volume24H", item.Volume)
+
+		rates = append(rates, domain.FundingRate{
+			Exchange:        c.Name(),
+			VenueSymbol:     item.Ticker,
+			Rate:            rate,
+			FundingInterval: fundingInterval,
+			Price:           &price,
+			Timestamp:       now,
+			NextFunding:     exchange.NextSettlement(now, fundingInterval),
+			Market:          exchange.Snapshot(exchange.Notional(openInterest, &price), volume, nil, &price),
+			Issues:          parser.Issues(),
+		})
+	}
+
+	c.logger.Info("fetched funding rates",
+		zap.String("exchange", c.Name()),
+		zap.Int("count", len(rates)),
+	)
+
+	return rates, nil
+}
+
+// FetchFundingHistory pages backwards through the settled funding of ticker
+// until from is reached. The indexer pages by block height, each page asks
+// for rows at or below the height right before the oldest row seen.
+func (c *Client) FetchFundingHistory(ctx context.Context, ticker string, from time.Time) ([]domain.FundingRate, error) {
+	var rates []domain.FundingRate
+	var parser exchange.FieldParser
+
+	beforeHeight := ""
+	for {
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(historyPageSize))
+		if beforeHeight != "" {
+			query.Set("effectiveBeforeOrAtHeight", beforeHeight)
+		}
+
+		pageUrl := fmt.Sprintf("%s/v4/historicalFunding/%s?%s", c.config.BaseURL, url.PathEscape(ticker), query.Encode())
+
+		var historyResp historyResponse
+		if err := c.httpClient.GetJSON(ctx, pageUrl, &historyResp); err != nil {
+			return nil, fmt.Errorf("fetch history page: %w", err)
+		}
+
+		page := historyResp.HistoricalFunding
+		for _, item := range page {
+			if item.EffectiveAt.Before(from) {
+				return rates, nil
+			}
+
+			rate := parser.Float("rate", item.Rate)
+			price := parser.Float("price", item.Price)
+			settledAt := item.EffectiveAt
+
+			rates = append(rates, domain.FundingRate{
+				Exchange:        c.Name(),
+				VenueSymbol:     item.Ticker,
+				Rate:            rate,
+				FundingInterval: fundingInterval,
+				Price:           &price,
+				Timestamp:       settledAt,
+				NextFunding:     &settledAt,
+				Issues:          parser.Issues(),
+			})
+		}
+
+		if len(page) < historyPageSize {
+			return rates, nil
+		}
+
+		height, err := strconv.ParseInt(page[len(page)-1].EffectiveAtHeight, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse effectiveAtHeight: %w", err)
+		}
+		if height <= 1 {
+			return rates, nil
+		}
+		beforeHeight = strconv.FormatInt(height-1, 10)
+	}
+}
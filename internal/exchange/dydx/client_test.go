@@ -0,0 +1,193 @@
+package dydx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+type historyRow struct {
+	Ticker            string    `json:"ticker"`
+	Rate              string    `json:"rate"`
+	Price             string    `json:"price"`
+	EffectiveAt       time.Time `json:"effectiveAt"`
+	EffectiveAtHeight string    `json:"effectiveAtHeight"`
+}
+
+// historyIndexer serves rows newest first the way the indexer pages them,
+// by limit and effectiveBeforeOrAtHeight.
+type historyIndexer struct {
+	rows []historyRow
+
+	mu       sync.Mutex
+	requests []string
+}
+
+// newHistoryIndexer holds count hourly settlements, the newest at latest
+// with height 1000 and one block per settlement below it.
+func newHistoryIndexer(count int, latest time.Time) *historyIndexer {
+	idx := &historyIndexer{}
+	for i := 0; i < count; i++ {
+		idx.rows = append(idx.rows, historyRow{
+			Ticker:            "BTC-USD",
+			Rate:              fmt.Sprintf("0.00000%d", i%10),
+			Price:             "65000.5",
+			EffectiveAt:       latest.Add(-time.Duration(i) * time.Hour),
+			EffectiveAtHeight: strconv.Itoa(1000 - i),
+		})
+	}
+
+	return idx
+}
+
+func (idx *historyIndexer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v4/historicalFunding/BTC-USD" {
+		http.NotFound(w, r)
+		return
+	}
+
+	idx.mu.Lock()
+	idx.requests = append(idx.requests, r.URL.RawQuery)
+	idx.mu.Unlock()
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, "missing limit", http.StatusBadRequest)
+		return
+	}
+	maxHeight := 1 << 30
+	if before := r.URL.Query().Get("effectiveBeforeOrAtHeight"); before != "" {
+		if maxHeight, err = strconv.Atoi(before); err != nil {
+			http.Error(w, "bad height", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page := []historyRow{}
+	for _, row := range idx.rows {
+		height, _ := strconv.Atoi(row.EffectiveAtHeight)
+		if height <= maxHeight && len(page) < limit {
+			page = append(page, row)
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"historicalFunding": page})
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return NewClient(exchange.Config{Name: "dydx", BaseURL: srv.URL, HTTP: exchange.HTTPConfig{MaxAttempts: 1}}, zap.NewNop())
+}
+
+func TestFetchFundingHistoryPaginates(t *testing.T) {
+	latest := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		rows         int
+		from         time.Time
+		wantRows     int
+		wantRequests int
+	}{
+		{
+			name:         "all pages",
+			rows:         250,
+			from:         latest.Add(-1000 * time.Hour),
+			wantRows:     250,
+			wantRequests: 3,
+		},
+		{
+			name:         "stops at from",
+			rows:         250,
+			from:         latest.Add(-149 * time.Hour),
+			wantRows:     150,
+			wantRequests: 2,
+		},
+		{
+			name:         "exact page size",
+			rows:         historyPageSize,
+			from:         latest.Add(-1000 * time.Hour),
+			wantRows:     historyPageSize,
+			wantRequests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := newHistoryIndexer(tt.rows, latest)
+			client := newTestClient(t, idx)
+
+			rates, err := client.FetchFundingHistory(context.Background(), "BTC-USD", tt.from)
+			if err != nil {
+				t.Fatalf("FetchFundingHistory: %v", err)
+			}
+
+			if len(rates) != tt.wantRows {
+				t.Errorf("got %d rates, want %d", len(rates), tt.wantRows)
+			}
+			if len(idx.requests) != tt.wantRequests {
+				t.Errorf("made %d requests, want %d: %v", len(idx.requests), tt.wantRequests, idx.requests)
+			}
+
+			// no settlement is returned twice or skipped
+			for i, rate := range rates {
+				want := latest.Add(-time.Duration(i) * time.Hour)
+				if !rate.Timestamp.Equal(want) {
+					t.Fatalf("rates[%d].Timestamp = %v, want %v", i, rate.Timestamp, want)
+				}
+				if rate.Timestamp.Before(tt.from) {
+					t.Fatalf("rates[%d] at %v is before from", i, rate.Timestamp)
+				}
+			}
+
+			if len(rates) > 0 {
+				first := rates[0]
+				if first.Exchange != "dydx" || first.VenueSymbol != "BTC-USD" || first.FundingInterval != time.Hour {
+					t.Errorf("first rate = %+v", first)
+				}
+				if first.Price == nil || *first.Price != 65000.5 {
+					t.Errorf("first price = %v, want 65000.5", first.Price)
+				}
+				if first.NextFunding == nil || !first.NextFunding.Equal(first.Timestamp) {
+					t.Errorf("NextFunding = %v, want the settlement time", first.NextFunding)
+				}
+			}
+
+			if len(idx.requests) > 1 {
+				if want := "effectiveBeforeOrAtHeight=900&limit=100"; idx.requests[1] != want {
+					t.Errorf("second request = %s, want %s", idx.requests[1], want)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchFundingHistoryFailedPage(t *testing.T) {
+	idx := newHistoryIndexer(250, time.Now())
+	calls := 0
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		idx.ServeHTTP(w, r)
+	}))
+
+	if _, err := client.FetchFundingHistory(context.Background(), "BTC-USD", time.Time{}); err == nil {
+		t.Fatal("FetchFundingHistory succeeded, want the error of the second page")
+	}
+}
@@ -2,11 +2,7 @@ package extended
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
 	"time"
 
 	"github.com/fiensola/funding/internal/domain"
@@ -14,36 +10,32 @@ import (
 	"go.uber.org/zap"
 )
 
+// fundingInterval is the settlement period of extended funding.
+const fundingInterval = time.Hour
+
 type Client struct {
 	config     exchange.Config
-	httpClient *http.Client
+	httpClient *exchange.HTTPClient
 	logger     *zap.Logger
 }
 
-func NewClient(config exchange.Config, logger *zap.Logger) *Client {
-	transport := &http.Transport{}
-
-	if config.Proxy != "" {
-		proxyUrl, err := url.Parse(config.Proxy)
-		if err == nil && proxyUrl != nil {
-			transport.Proxy = http.ProxyURL(proxyUrl)
-		}
-	}
-
-	httpClient := &http.Client{
-		Timeout:   5 * time.Second,
-		Transport: transport,
-	}
+func init() {
+	exchange.Register("extended", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger), nil
+	})
+}
 
+func NewClient(config exchange.Config, logger *zap.Logger) *Client {
 	return &Client{
 		config:     config,
-		httpClient: httpClient,
+		httpClient: exchange.NewHTTPClient(config, logger),
 		logger:     logger,
 	}
 }
 
+// Name returns the name of the config block, rows are stored under it.
 func (c *Client) Name() string {
-	return "extended"
+	return c.config.Name
 }
 
 type fundingResponse struct {
@@ -52,7 +44,12 @@ type fundingResponse struct {
 		Symbol      string  `json:"assetName"`
 		IsActive    bool    `json:"active"`
 		MarketStats struct {
-			Rate string `json:"fundingRate"`
+			Rate         string `json:"fundingRate"`
+			MarkPrice    string `json:"markPrice"`
+			IndexPrice   string `json:"indexPrice"`
+			OpenInterest string `json:"openInterest"`
+			Volume       string `json:"dailyVolume"`
+			NextFunding  int64  `json:"nextFundingRate"` // despite the name a ms timestamp
 		} `json:"marketStats"`
 	} `json:"data"`
 	Status string `json:"status"`
@@ -61,39 +58,36 @@ type fundingResponse struct {
 func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
 	url := fmt.Sprintf("%s/v1/info/markets", c.config.BaseURL)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
 	var fundingResp fundingResponse
-	if err := json.NewDecoder(resp.Body).Decode(&fundingResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := c.httpClient.GetJSON(ctx, url, &fundingResp); err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
 	rates := make([]domain.FundingRate, 0)
 
+	var parser exchange.FieldParser
 	for _, item := range fundingResp.Data {
 		if item.IsActive {
-			rate, _ := strconv.ParseFloat(item.MarketStats.Rate, 64)
+			rate := parser.Float("marketStats.fundingRate", item.MarketStats.Rate)
+			price := parser.Float("marketStats.markPrice", item.MarketStats.MarkPrice)
+			index := parser.OptionalFloat("marketStats.indexPrice", item.MarketStats.IndexPrice)
+			openInterest := parser.OptionalFloat("marketStats.openInterest", item.MarketStats.OpenInterest)
+			volume := parser.OptionalFloat("marketStats.dailyVolume", item.MarketStats.Volume)
+			nextFunding := exchange.UnixMilli(item.MarketStats.NextFunding)
+			if nextFunding == nil {
+				nextFunding = exchange.NextSettlement(now, fundingInterval)
+			}
 			rates = append(rates, domain.FundingRate{
-				Exchange:  c.Name(),
-				Symbol:    item.Symbol,
-				Rate:      rate,
-				Timestamp: now,
+				Exchange:        c.Name(),
+				VenueSymbol:     item.Symbol,
+				Rate:            rate,
+				FundingInterval: fundingInterval,
+				Price:           &price,
+				Timestamp:       now,
+				NextFunding:     nextFunding,
+				Market:          exchange.Snapshot(openInterest, volume, &price, index),
+				Issues:          parser.Issues(),
 			})
 		}
 	}
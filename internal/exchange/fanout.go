@@ -0,0 +1,119 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultConcurrency is used by FanOut when no limit is configured.
+const DefaultConcurrency = 8
+
+// PartialError reports the keys that failed during a fan out. Results of
+// the keys that succeeded are returned next to it.
+type PartialError struct {
+	Exchange string
+	Total    int
+	Failures map[string]error
+}
+
+func (e *PartialError) Error() string {
+	keys := make([]string, 0, len(e.Failures))
+	for key := range e.Failures {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	const maxListed = 5
+	details := make([]string, 0, maxListed)
+	for i, key := range keys {
+		if i == maxListed {
+			details = append(details, fmt.Sprintf("and %d more", len(keys)-maxListed))
+			break
+		}
+		details = append(details, fmt.Sprintf("%s: %v", key, e.Failures[key]))
+	}
+
+	return fmt.Sprintf("%s: %d of %d requests failed: %s",
+		e.Exchange, len(e.Failures), e.Total, strings.Join(details, "; "))
+}
+
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, err := range e.Failures {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// AllFailed reports whether no key succeeded.
+func (e *PartialError) AllFailed() bool {
+	return len(e.Failures) >= e.Total
+}
+
+// FanOut calls fn for every key with at most concurrency calls in flight and
+// returns the results in key order. When some calls fail the error is a
+// *PartialError listing them.
+func FanOut[T any](
+	ctx context.Context,
+	exchange string,
+	keys []string,
+	concurrency int,
+	fn func(ctx context.Context, key string) ([]T, error),
+) ([]T, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	results := make([][]T, len(keys))
+
+	var mu sync.Mutex
+	failures := make(map[string]error)
+
+	eg := errgroup.Group{}
+	eg.SetLimit(concurrency)
+
+	for i, key := range keys {
+		eg.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				mu.Lock()
+				failures[key] = err
+				mu.Unlock()
+				return nil
+			}
+
+			res, err := fn(ctx, key)
+			if err != nil {
+				mu.Lock()
+				failures[key] = err
+				mu.Unlock()
+				return nil
+			}
+
+			results[i] = res
+			return nil
+		})
+	}
+
+	eg.Wait()
+
+	var all []T
+	for _, res := range results {
+		all = append(all, res...)
+	}
+
+	if len(failures) > 0 {
+		return all, &PartialError{
+			Exchange: exchange,
+			Total:    len(keys),
+			Failures: failures,
+		}
+	}
+
+	return all, nil
+}
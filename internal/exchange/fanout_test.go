@@ -0,0 +1,178 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fanOutKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("K%02d", i)
+	}
+
+	return keys
+}
+
+func TestFanOutLimitsConcurrency(t *testing.T) {
+	tests := []struct {
+		concurrency int
+		want        int
+	}{
+		{concurrency: 3, want: 3},
+		{concurrency: 0, want: DefaultConcurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.concurrency), func(t *testing.T) {
+			var inFlight, peak atomic.Int32
+			_, err := FanOut(context.Background(), "fake", fanOutKeys(4*tt.want), tt.concurrency,
+				func(ctx context.Context, key string) ([]string, error) {
+					n := inFlight.Add(1)
+					defer inFlight.Add(-1)
+					for {
+						old := peak.Load()
+						if n <= old || peak.CompareAndSwap(old, n) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					return nil, nil
+				})
+			if err != nil {
+				t.Fatalf("FanOut: %v", err)
+			}
+
+			if got := int(peak.Load()); got != tt.want {
+				t.Errorf("peak concurrency = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFanOutKeepsKeyOrder(t *testing.T) {
+	keys := fanOutKeys(8)
+
+	// later keys finish first
+	got, err := FanOut(context.Background(), "fake", keys, len(keys),
+		func(ctx context.Context, key string) ([]string, error) {
+			i := slices.Index(keys, key)
+			time.Sleep(time.Duration(len(keys)-i) * time.Millisecond)
+			return []string{key + "a", key + "b"}, nil
+		})
+	if err != nil {
+		t.Fatalf("FanOut: %v", err)
+	}
+
+	var want []string
+	for _, key := range keys {
+		want = append(want, key+"a", key+"b")
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("results = %v, want %v", got, want)
+	}
+}
+
+func TestFanOutPartialError(t *testing.T) {
+	down := errors.New("timeout")
+	tests := []struct {
+		name      string
+		keys      int
+		failing   []string
+		results   []string
+		allFailed bool
+		message   string
+	}{
+		{
+			name:    "no failures",
+			keys:    3,
+			results: []string{"K00", "K01", "K02"},
+		},
+		{
+			name:    "some failed",
+			keys:    3,
+			failing: []string{"K01"},
+			results: []string{"K00", "K02"},
+			message: "fake: 1 of 3 requests failed: K01: timeout",
+		},
+		{
+			name:      "all failed",
+			keys:      2,
+			failing:   []string{"K00", "K01"},
+			allFailed: true,
+			message:   "fake: 2 of 2 requests failed: K00: timeout; K01: timeout",
+		},
+		{
+			name:    "long lists are cut",
+			keys:    8,
+			failing: fanOutKeys(7),
+			results: []string{"K07"},
+			message: "fake: 7 of 8 requests failed: K00: timeout; K01: timeout; K02: timeout; K03: timeout; K04: timeout; and 2 more",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FanOut(context.Background(), "fake", fanOutKeys(tt.keys), 2,
+				func(ctx context.Context, key string) ([]string, error) {
+					if slices.Contains(tt.failing, key) {
+						return nil, down
+					}
+					return []string{key}, nil
+				})
+
+			if !slices.Equal(got, tt.results) {
+				t.Errorf("results = %v, want %v", got, tt.results)
+			}
+
+			if len(tt.failing) == 0 {
+				if err != nil {
+					t.Fatalf("err = %v, want none", err)
+				}
+				return
+			}
+
+			var partial *PartialError
+			if !errors.As(err, &partial) {
+				t.Fatalf("err = %v, want a *PartialError", err)
+			}
+			if partial.Exchange != "fake" || partial.Total != tt.keys || len(partial.Failures) != len(tt.failing) {
+				t.Errorf("partial = %+v, want %d of %d failed on fake", partial, len(tt.failing), tt.keys)
+			}
+			if partial.AllFailed() != tt.allFailed {
+				t.Errorf("AllFailed = %v, want %v", partial.AllFailed(), tt.allFailed)
+			}
+			if !errors.Is(err, down) {
+				t.Errorf("err = %v does not wrap the failures", err)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("message = %q, want %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestFanOutSkipsKeysAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	_, err := FanOut(ctx, "fake", fanOutKeys(3), 1,
+		func(ctx context.Context, key string) ([]string, error) {
+			calls.Add(1)
+			return []string{key}, nil
+		})
+
+	if n := calls.Load(); n != 0 {
+		t.Errorf("fn called %d times after cancel", n)
+	}
+	var partial *PartialError
+	if !errors.As(err, &partial) || !partial.AllFailed() || !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want every key failed with context canceled", err)
+	}
+}
@@ -0,0 +1,141 @@
+package gmx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+// fundingInterval is the period rates are quoted for. GMX accrues funding
+// and borrow fees every second, so there is no settlement and any interval
+// would do.
+const fundingInterval = time.Hour
+
+type Client struct {
+	config     exchange.Config
+	options    options
+	httpClient *exchange.HTTPClient
+	logger     *zap.Logger
+}
+
+type options struct {
+	// RateDecimals is the fixed point precision of the raw rates.
+	RateDecimals int `mapstructure:"rate_decimals"`
+	// RatePeriod is the period a raw rate covers.
+	RatePeriod time.Duration `mapstructure:"rate_period"`
+}
+
+func init() {
+	exchange.Register("gmx", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger)
+	})
+}
+
+func NewClient(config exchange.Config, logger *zap.Logger) (*Client, error) {
+	options := options{
+		RateDecimals: 30,
+		RatePeriod:   time.Second,
+	}
+	if err := config.DecodeOptions(&options); err != nil {
+		return nil, err
+	}
+	if options.RatePeriod <= 0 {
+		return nil, fmt.Errorf("rate_period must be positive")
+	}
+
+	return &Client{
+		config:     config,
+		options:    options,
+		httpClient: exchange.NewHTTPClient(config, logger),
+		logger:     logger,
+	}, nil
+}
+
+// Name returns the name of the config block, rows are stored under it.
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+type marketsResponse struct {
+	Markets []struct {
+		Name              string `json:"name"`
+		IndexToken        string `json:"indexToken"`
+		IsListed          bool   `json:"isListed"`
+		OpenInterestLong  string `json:"openInterestLong"`
+		OpenInterestShort string `json:"openInterestShort"`
+		// funding is positive when the side receives, GMX UI convention
+		FundingRateLong    string `json:"fundingRateLong"`
+		FundingRateShort   string `json:"fundingRateShort"`
+		BorrowingRateLong  string `json:"borrowingRateLong"`
+		BorrowingRateShort string `json:"borrowingRateShort"`
+	} `json:"markets"`
+}
+
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	url := fmt.Sprintf("%s/markets/info", c.config.BaseURL)
+
+	var marketsResp marketsResponse
+	if err := c.httpClient.GetJSON(ctx, url, &marketsResp); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	scale := math.Pow10(-c.options.RateDecimals) * float64(fundingInterval) / float64(c.options.RatePeriod)
+	usdScale := math.Pow10(-30)
+
+	// several pools can back the same index token, keep the deepest one so
+	// the venue reports one row per asset
+	best := make(map[string]domain.FundingRate)
+	bestOI := make(map[string]float64)
+
+	var parser exchange.FieldParser
+	for _, item := range marketsResp.Markets {
+		if !item.IsListed {
+			continue
+		}
+
+		long := -parser.Float("fundingRateLong", item.FundingRateLong) * scale
+		short := -parser.Float("fundingRateShort", item.FundingRateShort) * scale
+		longBorrow := parser.Float("borrowingRateLong", item.BorrowingRateLong) * scale
+		shortBorrow := parser.Float("borrowingRateShort", item.BorrowingRateShort) * scale
+		openInterest := (parser.Float("openInterestLong", item.OpenInterestLong) +
+			parser.Float("openInterestShort", item.OpenInterestShort)) * usdScale
+
+		rate := domain.FundingRate{
+			Exchange:        c.Name(),
+			VenueSymbol:     item.Name,
+			Rate:            long,
+			LongRate:        &long,
+			ShortRate:       &short,
+			LongBorrowRate:  &longBorrow,
+			ShortBorrowRate: &shortBorrow,
+			FundingInterval: fundingInterval,
+			Timestamp:       now,
+			Market:          exchange.Snapshot(&openInterest, nil, nil, nil),
+			Issues:          parser.Issues(),
+		}
+
+		if prev, ok := best[item.IndexToken]; ok && len(prev.Issues) == 0 && bestOI[item.IndexToken] >= openInterest {
+			continue
+		}
+		best[item.IndexToken] = rate
+		bestOI[item.IndexToken] = openInterest
+	}
+
+	rates := make([]domain.FundingRate, 0, len(best))
+	for _, rate := range best {
+		rates = append(rates, rate)
+	}
+
+	c.logger.Info("fetched funding rates",
+		zap.String("exchange", c.Name()),
+		zap.Int("count", len(rates)),
+	)
+
+	return rates, nil
+}
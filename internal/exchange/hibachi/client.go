@@ -2,25 +2,30 @@ package hibachi
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
+	"sort"
 	"time"
 
 	"github.com/fiensola/funding/internal/domain"
 	"github.com/fiensola/funding/internal/exchange"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
 )
 
+// fundingInterval is the settlement period of hibachi funding.
+const fundingInterval = 8 * time.Hour
+
 type Client struct {
 	config     exchange.Config
-	httpClient *http.Client
+	options    options
+	httpClient *exchange.HTTPClient
 	logger     *zap.Logger
 }
 
+type options struct {
+	// Concurrency limits the per symbol requests in flight.
+	Concurrency int `mapstructure:"concurrency"`
+}
+
 type infoResponse struct {
 	Contracts []struct {
 		Pair   string `json:"symbol"`
@@ -30,119 +35,99 @@ type infoResponse struct {
 }
 
 type symbolResponse struct {
-	Price    string `json:"markPrice"`
-	RateInfo struct {
-		Rate string `json:"estimatedFundingRate"`
+	Price      string `json:"markPrice"`
+	IndexPrice string `json:"spotPrice"`
+	RateInfo   struct {
+		Rate        string `json:"estimatedFundingRate"`
+		NextFunding int64  `json:"nextFundingTimestamp"` // seconds
 	} `json:"fundingRateEstimation"`
 }
 
-func NewClient(config exchange.Config, logger *zap.Logger) *Client {
-	transport := &http.Transport{}
-
-	if config.Proxy != "" {
-		proxyUrl, err := url.Parse(config.Proxy)
-		if err == nil && proxyUrl != nil {
-			transport.Proxy = http.ProxyURL(proxyUrl)
-		}
-	}
+func init() {
+	exchange.Register("hibachi", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger)
+	})
+}
 
-	httpClient := &http.Client{
-		Timeout:   5 * time.Second,
-		Transport: transport,
+func NewClient(config exchange.Config, logger *zap.Logger) (*Client, error) {
+	var options options
+	if err := config.DecodeOptions(&options); err != nil {
+		return nil, err
 	}
 
 	return &Client{
 		config:     config,
-		httpClient: httpClient,
+		options:    options,
+		httpClient: exchange.NewHTTPClient(config, logger),
 		logger:     logger,
-	}
+	}, nil
 }
 
+// Name returns the name of the config block, rows are stored under it.
 func (c *Client) Name() string {
-	return "hibachi"
+	return c.config.Name
 }
 
 func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
-	rates := make([]domain.FundingRate, 0)
-
 	symbols, err := c.getSymbols(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	eg := errgroup.Group{}
-	for symbol, pair := range symbols {
-		eg.Go(func() error {
-			url := fmt.Sprintf("%s/market/data/prices?symbol=%s", c.config.BaseURL, pair)
-
-			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-			if err != nil {
-				return fmt.Errorf("create request: %w", err)
-			}
-
-			req.Header.Set("Content-Type", "application/json")
-
-			resp, err := c.httpClient.Do(req)
-			if err != nil {
-				return fmt.Errorf("execute request: %w", err)
-			}
-			defer resp.Body.Close()
+	keys := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		keys = append(keys, symbol)
+	}
+	sort.Strings(keys)
 
-			if resp.StatusCode != http.StatusOK {
-				return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-			}
+	rates, err := exchange.FanOut(ctx, c.Name(), keys, c.options.Concurrency,
+		func(ctx context.Context, symbol string) ([]domain.FundingRate, error) {
+			url := fmt.Sprintf("%s/market/data/prices?symbol=%s", c.config.BaseURL, symbols[symbol])
 
 			var symbolResponse symbolResponse
-			if err := json.NewDecoder(resp.Body).Decode(&symbolResponse); err != nil {
-				return fmt.Errorf("decode response: %w", err)
+			if err := c.httpClient.GetJSON(ctx, url, &symbolResponse); err != nil {
+				return nil, err
 			}
 
-			now := time.Now()
-			rate, _ := strconv.ParseFloat(symbolResponse.RateInfo.Rate, 64)
-			rates = append(rates, domain.FundingRate{
-				Exchange:  c.Name(),
-				Symbol:    symbol,
-				Rate:      rate,
-				Timestamp: now,
-			})
-
-			return nil
-		})
-	}
+			var parser exchange.FieldParser
+			rate := parser.Float("fundingRateEstimation.estimatedFundingRate", symbolResponse.RateInfo.Rate)
+			price := parser.Float("markPrice", symbolResponse.Price)
+			index := parser.OptionalFloat("spotPrice", symbolResponse.IndexPrice)
 
-	eg.Wait()
+			now := time.Now()
+			nextFunding := exchange.NextSettlement(now, fundingInterval)
+			if ts := symbolResponse.RateInfo.NextFunding; ts > 0 {
+				nextFunding = exchange.UnixMilli(ts * 1000)
+			}
+			return []domain.FundingRate{{
+				Exchange:        c.Name(),
+				Symbol:          symbol,
+				VenueSymbol:     symbols[symbol],
+				Rate:            rate,
+				FundingInterval: fundingInterval,
+				Price:           &price,
+				Timestamp:       now,
+				NextFunding:     nextFunding,
+				Market:          exchange.Snapshot(nil, nil, &price, index),
+				Issues:          parser.Issues(),
+			}}, nil
+		},
+	)
 
 	c.logger.Info("fetched funding rates",
 		zap.String("exchange", c.Name()),
 		zap.Int("count", len(rates)),
 	)
 
-	return rates, nil
+	return rates, err
 }
 
 func (c *Client) getSymbols(ctx context.Context) (map[string]string, error) {
 	infoUrl := fmt.Sprintf("%s/market/exchange-info", c.config.BaseURL)
 
-	reqInfo, err := http.NewRequestWithContext(ctx, "GET", infoUrl, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	reqInfo.Header.Set("Content-Type", "application/json")
-
-	respInfo, err := c.httpClient.Do(reqInfo)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
-	}
-	defer respInfo.Body.Close()
-
-	if respInfo.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", respInfo.StatusCode)
-	}
-
 	var infoResp infoResponse
-	if err := json.NewDecoder(respInfo.Body).Decode(&infoResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := c.httpClient.GetJSON(ctx, infoUrl, &infoResp); err != nil {
+		return nil, err
 	}
 
 	if infoResp.Status != "NORMAL" {
@@ -0,0 +1,363 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// HTTPConfig controls timeouts, retries and rate limiting of HTTPClient.
+// Zero values fall back to the defaults below.
+type HTTPConfig struct {
+	Timeout     time.Duration
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// RateLimit is the number of requests per second allowed per host,
+	// zero disables the limiter.
+	RateLimit float64
+	Burst     int
+}
+
+const (
+	defaultTimeout     = 5 * time.Second
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 200 * time.Millisecond
+	defaultMaxDelay    = 5 * time.Second
+)
+
+func (c HTTPConfig) withDefaults() HTTPConfig {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+	if c.BaseDelay <= 0 {
+		c.BaseDelay = defaultBaseDelay
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = defaultMaxDelay
+	}
+	if c.RateLimit > 0 && c.Burst <= 0 {
+		c.Burst = 1
+	}
+
+	return c
+}
+
+// StatusError is returned for responses with a non 200 status code.
+type StatusError struct {
+	Code       int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.Code)
+}
+
+// Temporary reports whether the request may succeed if retried.
+func (e *StatusError) Temporary() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+}
+
+// HTTPClient is the http client shared by exchange adapters. It retries
+// transient failures with jittered exponential backoff, honours Retry-After
+// and limits the request rate per host.
+type HTTPClient struct {
+	client *http.Client
+	config HTTPConfig
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	limiters map[string]*RateLimiter
+}
+
+func NewHTTPClient(config Config, logger *zap.Logger) *HTTPClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.Proxy != "" {
+		proxyUrl, err := url.Parse(config.Proxy)
+		if err == nil && proxyUrl != nil {
+			transport.Proxy = http.ProxyURL(proxyUrl)
+		} else {
+			logger.Warn("invalid proxy url, ignoring", zap.Error(err))
+		}
+	}
+
+	httpConfig := config.HTTP.withDefaults()
+
+	return &HTTPClient{
+		client: &http.Client{
+			Timeout:   httpConfig.Timeout,
+			Transport: transport,
+		},
+		config:   httpConfig,
+		logger:   logger,
+		limiters: make(map[string]*RateLimiter),
+	}
+}
+
+// GetJSON sends a GET request and decodes the JSON response into out.
+func (c *HTTPClient) GetJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	return c.doJSON(req, out)
+}
+
+// PostJSON sends body encoded as JSON and decodes the JSON response into out.
+func (c *HTTPClient) PostJSON(ctx context.Context, url string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.doJSON(req, out)
+}
+
+func (c *HTTPClient) doJSON(req *http.Request, out any) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// Do sends the request, retrying transient failures. Any status other than
+// 200 is returned as *StatusError. Requests with a body must be replayable
+// through GetBody, which http.NewRequest sets for the common body types.
+func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	var lastErr error
+	for attempt := 0; attempt < c.config.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := c.backoff(attempt, lastErr)
+			if delay < 0 {
+				break
+			}
+
+			c.logger.Debug("retrying request",
+				zap.String("url", req.URL.String()),
+				zap.Int("attempt", attempt+1),
+				zap.Duration("delay", delay),
+				zap.Error(lastErr),
+			)
+
+			if err := sleep(ctx, delay); err != nil {
+				return nil, err
+			}
+
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, fmt.Errorf("rewind request body: %w", err)
+				}
+				req.Body = body
+			}
+		}
+
+		if err := c.limiter(req.URL.Host).Wait(ctx); err != nil {
+			return nil, err
+		}
+		if err := limiterFrom(ctx).Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("execute request: %w", ctx.Err())
+			}
+			lastErr = fmt.Errorf("execute request: %w", err)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+
+		statusErr := &StatusError{
+			Code:       resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		resp.Body.Close()
+
+		lastErr = statusErr
+		if !statusErr.Temporary() {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+// backoff returns the delay before the next attempt or -1 when retrying
+// makes no sense.
+func (c *HTTPClient) backoff(attempt int, lastErr error) time.Duration {
+	var statusErr *StatusError
+	if errors.As(lastErr, &statusErr) && statusErr.RetryAfter > 0 {
+		if statusErr.RetryAfter > c.config.MaxDelay {
+			return -1
+		}
+		return statusErr.RetryAfter
+	}
+
+	// full jitter
+	ceil := c.config.BaseDelay << (attempt - 1)
+	if ceil <= 0 || ceil > c.config.MaxDelay {
+		ceil = c.config.MaxDelay
+	}
+
+	return rand.N(ceil) + 1
+}
+
+func (c *HTTPClient) limiter(host string) *RateLimiter {
+	if c.config.RateLimit <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	limiter, ok := c.limiters[host]
+	if !ok {
+		limiter = NewRateLimiter(c.config.RateLimit, c.config.Burst)
+		c.limiters[host] = limiter
+	}
+
+	return limiter
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type limiterKey struct{}
+
+// WithLimiter returns a context that makes every attempt of the requests
+// sent with it, retries included, wait for limiter as well. It is meant
+// for endpoints with a budget of their own next to the host wide one.
+func WithLimiter(ctx context.Context, limiter *RateLimiter) context.Context {
+	return context.WithValue(ctx, limiterKey{}, limiter)
+}
+
+// limiterFrom returns the limiter set by WithLimiter, nil when none is.
+func limiterFrom(ctx context.Context) *RateLimiter {
+	limiter, _ := ctx.Value(limiterKey{}).(*RateLimiter)
+	return limiter
+}
+
+// RateLimiter is a minimal token bucket limiter, a nil limiter never blocks.
+// HTTPClient keeps one per host, adapters can add narrower budgets such as
+// a single endpoint on top.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a limiter allowing rate requests per second with
+// bursts of burst, or nil when rate is not positive.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	burst = max(burst, 1)
+
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (b *RateLimiter) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	// reserve the token now so concurrent callers queue up behind us
+	b.tokens--
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		// give the reservation back
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
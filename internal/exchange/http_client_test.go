@@ -0,0 +1,235 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestHTTPClient(config HTTPConfig) *HTTPClient {
+	if config.BaseDelay == 0 {
+		config.BaseDelay = time.Millisecond
+	}
+	if config.MaxDelay == 0 {
+		config.MaxDelay = 10 * time.Millisecond
+	}
+
+	return NewHTTPClient(Config{HTTP: config}, zap.NewNop())
+}
+
+func TestHTTPClientRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	if err := newTestHTTPClient(HTTPConfig{}).GetJSON(context.Background(), srv.URL, &out); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if !out.OK {
+		t.Error("response was not decoded")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestHTTPClientDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	err := newTestHTTPClient(HTTPConfig{}).GetJSON(context.Background(), srv.URL, &struct{}{})
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound {
+		t.Fatalf("err = %v, want status 404", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestHTTPClientRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		maxDelay   time.Duration
+		wantCalls  int32
+		wantErr    bool
+		minElapsed time.Duration
+	}{
+		{
+			name:       "honoured",
+			retryAfter: "1",
+			maxDelay:   2 * time.Second,
+			wantCalls:  2,
+			minElapsed: time.Second,
+		},
+		{
+			name:       "longer than max delay",
+			retryAfter: "10",
+			maxDelay:   100 * time.Millisecond,
+			wantCalls:  1,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) == 1 {
+					w.Header().Set("Retry-After", tt.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				io.WriteString(w, `{}`)
+			}))
+			defer srv.Close()
+
+			start := time.Now()
+			err := newTestHTTPClient(HTTPConfig{MaxDelay: tt.maxDelay}).GetJSON(context.Background(), srv.URL, &struct{}{})
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("elapsed = %v, want at least %v", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func TestHTTPClientReplaysBodyOnRetry(t *testing.T) {
+	var calls atomic.Int32
+	bodies := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+
+	payload := map[string]string{"type": "metaAndAssetCtxs"}
+	if err := newTestHTTPClient(HTTPConfig{}).PostJSON(context.Background(), srv.URL, payload, &struct{}{}); err != nil {
+		t.Fatalf("PostJSON: %v", err)
+	}
+
+	close(bodies)
+	want := `{"type":"metaAndAssetCtxs"}`
+	for body := range bodies {
+		if body != want {
+			t.Errorf("body = %q, want %q", body, want)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestHTTPClientRateLimitsPerHost(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{}`)
+	})
+	first := httptest.NewServer(handler)
+	defer first.Close()
+	second := httptest.NewServer(handler)
+	defer second.Close()
+
+	// 10 requests per second with no burst, every request after the
+	// first to a host waits 100ms
+	client := newTestHTTPClient(HTTPConfig{RateLimit: 10, Burst: 1})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := client.GetJSON(ctx, first.URL, &struct{}{}); err != nil {
+			t.Fatalf("GetJSON: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("3 requests to one host took %v, want at least 200ms", elapsed)
+	}
+
+	// the other host has its own bucket
+	start = time.Now()
+	if err := client.GetJSON(ctx, second.URL, &struct{}{}); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("first request to another host took %v, want no wait", elapsed)
+	}
+}
+
+func TestRateLimiterWaitHonoursContext(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	ctx := context.Background()
+
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v, want deadline exceeded", err)
+	}
+
+	var nilLimiter *RateLimiter
+	if err := nilLimiter.Wait(context.Background()); err != nil {
+		t.Errorf("nil limiter Wait = %v", err)
+	}
+}
+
+func TestHTTPClientChargesContextLimiterOnRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+
+	// one request per 100ms, the two retries each wait for a token
+	ctx := WithLimiter(context.Background(), NewRateLimiter(10, 1))
+
+	start := time.Now()
+	if err := newTestHTTPClient(HTTPConfig{}).GetJSON(ctx, srv.URL, &struct{}{}); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("3 attempts took %v, want at least 200ms", elapsed)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
@@ -0,0 +1,130 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+// fundingInterval is the settlement period of hyperliquid funding.
+const fundingInterval = time.Hour
+
+type Client struct {
+	config     exchange.Config
+	httpClient *exchange.HTTPClient
+	logger     *zap.Logger
+}
+
+func init() {
+	exchange.Register("hyperliquid", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger), nil
+	})
+}
+
+func NewClient(config exchange.Config, logger *zap.Logger) *Client {
+	return &Client{
+		config:     config,
+		httpClient: exchange.NewHTTPClient(config, logger),
+		logger:     logger,
+	}
+}
+
+// Name returns the name of the config block, rows are stored under it.
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+type infoRequest struct {
+	Type string `json:"type"`
+}
+
+type meta struct {
+	Universe []struct {
+		Name       string `json:"name"`
+		IsDelisted bool   `json:"isDelisted"`
+	} `json:"universe"`
+}
+
+type assetCtx struct {
+	Funding      string `json:"funding"`
+	Premium      string `json:"premium"`
+	OpenInterest string `json:"openInterest"` // base currency
+	Volume       string `json:"dayNtlVlm"`
+	MarkPrice    string `json:"markPx"`
+	OraclePrice  string `json:"oraclePx"`
+}
+
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	url := fmt.Sprintf("%s/info", c.config.BaseURL)
+
+	// the response is a [meta, []assetCtx] tuple
+	var fundingResp []json.RawMessage
+	if err := c.httpClient.PostJSON(ctx, url, infoRequest{Type: "metaAndAssetCtxs"}, &fundingResp); err != nil {
+		return nil, err
+	}
+
+	if len(fundingResp) != 2 {
+		return nil, fmt.Errorf("unexpected response length: %d", len(fundingResp))
+	}
+
+	var info meta
+	if err := json.Unmarshal(fundingResp[0], &info); err != nil {
+		return nil, fmt.Errorf("decode meta: %w", err)
+	}
+
+	var ctxs []assetCtx
+	if err := json.Unmarshal(fundingResp[1], &ctxs); err != nil {
+		return nil, fmt.Errorf("decode asset contexts: %w", err)
+	}
+
+	if len(info.Universe) != len(ctxs) {
+		return nil, fmt.Errorf("universe has %d assets but got %d contexts", len(info.Universe), len(ctxs))
+	}
+
+	now := time.Now()
+	rates := make([]domain.FundingRate, 0, len(ctxs))
+
+	var parser exchange.FieldParser
+	for i, asset := range info.Universe {
+		if asset.IsDelisted {
+			continue
+		}
+
+		item := ctxs[i]
+		rate := parser.Float("funding", item.Funding)
+		mark := parser.Float("markPx", item.MarkPrice)
+		oracle := parser.OptionalFloat("oraclePx", item.OraclePrice)
+		premium := parser.OptionalFloat("premium", item.Premium)
+		openInterest := parser.OptionalFloat("openInterest", item.OpenInterest)
+		volume := parser.OptionalFloat("dayNtlVlm", item.Volume)
+
+		market := exchange.Snapshot(exchange.Notional(openInterest, &mark), volume, &mark, oracle)
+		if market != nil {
+			market.Premium = premium
+		}
+
+		rates = append(rates, domain.FundingRate{
+			Exchange:        c.Name(),
+			VenueSymbol:     asset.Name,
+			Rate:            rate,
+			FundingInterval: fundingInterval,
+			Price:           &mark,
+			Timestamp:       now,
+			NextFunding:     exchange.NextSettlement(now, fundingInterval),
+			Market:          market,
+			Issues:          parser.Issues(),
+		})
+	}
+
+	c.logger.Info("fetched funding rates",
+		zap.String("exchange", c.Name()),
+		zap.Int("count", len(rates)),
+	)
+
+	return rates, nil
+}
@@ -0,0 +1,137 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+// serveInfo answers POST /info with body.
+func serveInfo(t *testing.T, body []byte) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/info" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var req infoRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Type != "metaAndAssetCtxs" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return NewClient(exchange.Config{Name: "hyperliquid", BaseURL: srv.URL, HTTP: exchange.HTTPConfig{MaxAttempts: 1}}, zap.NewNop())
+}
+
+func readFixture(t *testing.T) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile("testdata/metaAndAssetCtxs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return body
+}
+
+func TestFetchFundingRates(t *testing.T) {
+	client := serveInfo(t, readFixture(t))
+
+	rates, err := client.FetchFundingRates(context.Background())
+	if err != nil {
+		t.Fatalf("FetchFundingRates: %v", err)
+	}
+
+	// FTT is delisted
+	if len(rates) != 3 {
+		t.Fatalf("got %d rates, want 3", len(rates))
+	}
+	for i, want := range []string{"BTC", "ETH", "kPEPE"} {
+		if rates[i].VenueSymbol != want {
+			t.Errorf("rates[%d].VenueSymbol = %s, want %s", i, rates[i].VenueSymbol, want)
+		}
+	}
+
+	btc := rates[0]
+	if btc.Exchange != "hyperliquid" {
+		t.Errorf("Exchange = %s", btc.Exchange)
+	}
+	if btc.FundingInterval != time.Hour {
+		t.Errorf("FundingInterval = %v, want 1h", btc.FundingInterval)
+	}
+	if btc.NextFunding == nil || btc.NextFunding.Sub(btc.Timestamp) > time.Hour {
+		t.Errorf("NextFunding = %v, want within the hour after %v", btc.NextFunding, btc.Timestamp)
+	}
+	if len(btc.Issues) != 0 {
+		t.Errorf("Issues = %v", btc.Issues)
+	}
+
+	checks := []struct {
+		name string
+		got  *float64
+		want float64
+	}{
+		{"rate", &btc.Rate, 0.0000125},
+		{"price", btc.Price, 65030},
+		{"mark price", btc.Market.MarkPrice, 65030},
+		{"index price", btc.Market.IndexPrice, 65012},
+		{"premium", btc.Market.Premium, 0.00021514},
+		// open interest is quoted in base currency and stored as notional
+		{"open interest", btc.Market.OpenInterest, 27543.21874 * 65030},
+		{"volume", btc.Market.Volume24h, 1834567123.4412},
+	}
+	for _, c := range checks {
+		if c.got == nil {
+			t.Errorf("%s is nil, want %v", c.name, c.want)
+			continue
+		}
+		if math.Abs(*c.got-c.want) > 1e-9*math.Max(1, math.Abs(c.want)) {
+			t.Errorf("%s = %v, want %v", c.name, *c.got, c.want)
+		}
+	}
+
+	if eth := rates[1]; eth.Rate != -0.0000031 || eth.Market == nil || *eth.Market.Premium != -0.0000312 {
+		t.Errorf("ETH rate = %v, market = %+v", eth.Rate, eth.Market)
+	}
+}
+
+func TestFetchFundingRatesLengthMismatch(t *testing.T) {
+	var resp []json.RawMessage
+	if err := json.Unmarshal(readFixture(t), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	var ctxs []json.RawMessage
+	if err := json.Unmarshal(resp[1], &ctxs); err != nil {
+		t.Fatal(err)
+	}
+	resp[1], _ = json.Marshal(ctxs[:len(ctxs)-1])
+	body, _ := json.Marshal(resp)
+
+	client := serveInfo(t, body)
+	if _, err := client.FetchFundingRates(context.Background()); err == nil {
+		t.Fatal("FetchFundingRates succeeded, want an error for 4 assets and 3 contexts")
+	}
+}
+
+func TestFetchFundingRatesMalformed(t *testing.T) {
+	client := serveInfo(t, []byte(`[{"universe":[]}]`))
+
+	if _, err := client.FetchFundingRates(context.Background()); err == nil {
+		t.Fatal("FetchFundingRates succeeded, want an error for a one element tuple")
+	}
+}
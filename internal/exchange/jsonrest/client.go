@@ -0,0 +1,197 @@
+// Package jsonrest implements an adapter configured entirely through the
+// exchange options, for venues that serve all rates from one JSON endpoint.
+package jsonrest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+type Client struct {
+	name       string
+	config     exchange.Config
+	mapping    mapping
+	httpClient *exchange.HTTPClient
+	logger     *zap.Logger
+}
+
+func init() {
+	exchange.Register("jsonrest", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger)
+	})
+}
+
+func NewClient(config exchange.Config, logger *zap.Logger) (*Client, error) {
+	mapping := defaultMapping()
+	if err := config.DecodeOptions(&mapping); err != nil {
+		return nil, err
+	}
+	if err := mapping.validate(); err != nil {
+		return nil, fmt.Errorf("invalid mapping: %w", err)
+	}
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("invalid mapping: base_url: required")
+	}
+
+	return &Client{
+		name:       config.Name,
+		config:     config,
+		mapping:    mapping,
+		httpClient: exchange.NewHTTPClient(config, logger),
+		logger:     logger,
+	}, nil
+}
+
+// Name returns the name of the config block, one jsonrest adapter can back
+// several venues.
+func (c *Client) Name() string {
+	return c.name
+}
+
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	url := c.config.BaseURL + c.mapping.Path
+
+	var body json.RawMessage
+	if err := c.httpClient.GetJSON(ctx, url, &body); err != nil {
+		return nil, err
+	}
+
+	// keep numbers as text so FieldParser sees what the venue sent
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	value, ok := lookup(doc, c.mapping.Items)
+	if !ok {
+		return nil, fmt.Errorf("items path %q not found", c.mapping.Items)
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("items path %q is %T, want an array", c.mapping.Items, value)
+	}
+
+	now := time.Now()
+	rates := make([]domain.FundingRate, 0, len(items))
+
+	var parser exchange.FieldParser
+	for _, item := range items {
+		if !c.matches(item) {
+			continue
+		}
+		rates = append(rates, c.parseRow(&parser, item, now))
+	}
+
+	c.logger.Info("fetched funding rates",
+		zap.String("exchange", c.Name()),
+		zap.Int("count", len(rates)),
+	)
+
+	return rates, nil
+}
+
+func (c *Client) matches(row any) bool {
+	if c.mapping.Where.Path == "" {
+		return true
+	}
+
+	value, ok := lookup(row, c.mapping.Where.Path)
+	if !ok {
+		return false
+	}
+
+	return fmt.Sprint(value) == c.mapping.Where.Equals
+}
+
+func (c *Client) parseRow(parser *exchange.FieldParser, row any, now time.Time) domain.FundingRate {
+	fields := c.mapping.Fields
+
+	// value returns the text at f, ok is false once a type mismatch was
+	// recorded so the value is not parsed into a second issue
+	value := func(name string, f field) (string, bool) {
+		raw, err := f.raw(row)
+		if err != nil {
+			parser.Fail(name, raw, err.Error())
+			return raw, false
+		}
+		return raw, true
+	}
+	optional := func(name string, f field) *float64 {
+		if f.Path == "" {
+			return nil
+		}
+		raw, ok := value(name, f)
+		if !ok {
+			return nil
+		}
+		return parser.OptionalFloat(name, raw)
+	}
+
+	// a mismatched symbol is still shown with the quarantined row
+	symbol, _ := value("symbol", fields.Symbol)
+	venueSymbol := c.mapping.Symbol.apply(symbol)
+
+	var rate float64
+	if raw, ok := value("rate", fields.Rate); ok {
+		rate = parser.Float("rate", raw) * c.mapping.RateScale
+	}
+	price := optional("price", fields.Price)
+	index := optional("index_price", fields.IndexPrice)
+	openInterest := optional("open_interest", fields.OpenInterest)
+	if c.mapping.OpenInterestInBase {
+		openInterest = exchange.Notional(openInterest, price)
+	}
+	volume := optional("volume", fields.Volume)
+
+	var nextFunding *time.Time
+	if raw, ok := value("next_funding", fields.NextFunding); ok {
+		nextFunding = c.nextFunding(parser, raw)
+	}
+	if nextFunding == nil {
+		nextFunding = exchange.NextSettlement(now, c.mapping.Interval)
+	}
+
+	return domain.FundingRate{
+		Exchange:        c.Name(),
+		VenueSymbol:     venueSymbol,
+		Rate:            rate,
+		FundingInterval: c.mapping.Interval,
+		Price:           price,
+		Timestamp:       now,
+		NextFunding:     nextFunding,
+		Market:          exchange.Snapshot(openInterest, volume, price, index),
+		Issues:          parser.Issues(),
+	}
+}
+
+// nextFunding accepts unix timestamps in the configured unit and RFC 3339.
+func (c *Client) nextFunding(parser *exchange.FieldParser, raw string) *time.Time {
+	if raw == "" {
+		return nil
+	}
+
+	if ts, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		if c.mapping.NextFundingUnit == "s" {
+			ts *= 1000
+		}
+		return exchange.UnixMilli(ts)
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		parser.Fail("next_funding", raw, "not a timestamp")
+		return nil
+	}
+
+	return &t
+}
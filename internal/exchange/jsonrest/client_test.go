@@ -0,0 +1,75 @@
+package jsonrest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+func TestParseRowRecordsTypeMismatchOnce(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"symbol": "BTC-PERP", "rate": "0.0001", "price": "65000", "oi": {"base": 1}, "next": [1]},
+			{"symbol": "ETH-PERP", "rate": {"value": 1}, "price": "3200"}
+		]`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(exchange.Config{
+		Name:    "example",
+		BaseURL: srv.URL,
+		Options: map[string]any{
+			"path": "/v1/markets",
+			"fields": map[string]any{
+				"symbol":        map[string]any{"path": "symbol"},
+				"rate":          map[string]any{"path": "rate"},
+				"price":         map[string]any{"path": "price", "type": "number"},
+				"open_interest": map[string]any{"path": "oi"},
+				"next_funding":  map[string]any{"path": "next"},
+			},
+		},
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	rates, err := client.FetchFundingRates(context.Background())
+	if err != nil {
+		t.Fatalf("FetchFundingRates: %v", err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("got %d rates, want 2", len(rates))
+	}
+
+	tests := []struct {
+		symbol string
+		fields []string
+	}{
+		{"BTC-PERP", []string{"price", "open_interest", "next_funding"}},
+		{"ETH-PERP", []string{"rate", "price"}},
+	}
+	for i, tt := range tests {
+		rate := rates[i]
+		if rate.VenueSymbol != tt.symbol {
+			t.Errorf("rates[%d].VenueSymbol = %s, want %s", i, rate.VenueSymbol, tt.symbol)
+		}
+
+		var fields []string
+		for _, issue := range rate.Issues {
+			fields = append(fields, issue.Field)
+		}
+		if len(fields) != len(tt.fields) {
+			t.Errorf("%s issues = %+v, want one for each of %v", tt.symbol, rate.Issues, tt.fields)
+			continue
+		}
+		for j := range fields {
+			if fields[j] != tt.fields[j] {
+				t.Errorf("%s issue %d is for %s, want %s", tt.symbol, j, fields[j], tt.fields[j])
+			}
+		}
+	}
+}
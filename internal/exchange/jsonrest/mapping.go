@@ -0,0 +1,245 @@
+package jsonrest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value types a field mapping accepts.
+const (
+	typeAuto   = "auto"
+	typeString = "string"
+	typeNumber = "number"
+)
+
+// mapping describes how to read rates from one venue response. It is decoded
+// from the exchange options, see config.yaml.example.
+type mapping struct {
+	// Path is appended to the base url.
+	Path string `mapstructure:"path"`
+	// Items is the dot path of the row array, empty when the response is
+	// the array itself.
+	Items  string `mapstructure:"items"`
+	Fields struct {
+		Symbol       field `mapstructure:"symbol"`
+		Rate         field `mapstructure:"rate"`
+		Price        field `mapstructure:"price"`
+		IndexPrice   field `mapstructure:"index_price"`
+		OpenInterest field `mapstructure:"open_interest"`
+		Volume       field `mapstructure:"volume"`
+		NextFunding  field `mapstructure:"next_funding"`
+	} `mapstructure:"fields"`
+	// Where keeps only rows whose field equals the given value.
+	Where struct {
+		Path   string `mapstructure:"path"`
+		Equals string `mapstructure:"equals"`
+	} `mapstructure:"where"`
+	Symbol symbolTransform `mapstructure:"symbol"`
+	// Interval is the settlement period the rate is quoted for.
+	Interval time.Duration `mapstructure:"interval"`
+	// RateScale multiplies the raw rate, e.g. 0.01 for percent values.
+	RateScale float64 `mapstructure:"rate_scale"`
+	// OpenInterestInBase marks open interest quoted in base currency, it
+	// is converted with the price.
+	OpenInterestInBase bool `mapstructure:"open_interest_in_base"`
+	// NextFundingUnit is the unit of numeric next funding timestamps.
+	NextFundingUnit string `mapstructure:"next_funding_unit"`
+}
+
+// field points at one value of a row.
+type field struct {
+	Path string `mapstructure:"path"`
+	// Type is string, number or auto, values of another JSON type are
+	// reported as issues.
+	Type string `mapstructure:"type"`
+}
+
+// symbolTransform turns venue symbols into something the normalizer
+// understands, steps run in field order.
+type symbolTransform struct {
+	// Split keeps the part before the separator, "BTC/USD" -> "BTC".
+	Split      string `mapstructure:"split"`
+	TrimPrefix string `mapstructure:"trim_prefix"`
+	TrimSuffix string `mapstructure:"trim_suffix"`
+	// Case is upper, lower or empty to keep it.
+	Case string `mapstructure:"case"`
+}
+
+func defaultMapping() mapping {
+	return mapping{
+		Interval:        8 * time.Hour,
+		RateScale:       1,
+		NextFundingUnit: "ms",
+	}
+}
+
+// validate reports every problem with the mapping at once, so a broken
+// config block can be fixed in one go.
+func (m mapping) validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if m.Path == "" {
+		fail("path: required")
+	}
+	if err := validatePath(m.Items); m.Items != "" && err != nil {
+		fail("items: %w", err)
+	}
+
+	fields := []struct {
+		name     string
+		field    field
+		required bool
+	}{
+		{"symbol", m.Fields.Symbol, true},
+		{"rate", m.Fields.Rate, true},
+		{"price", m.Fields.Price, false},
+		{"index_price", m.Fields.IndexPrice, false},
+		{"open_interest", m.Fields.OpenInterest, false},
+		{"volume", m.Fields.Volume, false},
+		{"next_funding", m.Fields.NextFunding, false},
+	}
+	for _, f := range fields {
+		if f.field.Path == "" {
+			if f.required {
+				fail("fields.%s.path: required", f.name)
+			}
+			continue
+		}
+		if err := validatePath(f.field.Path); err != nil {
+			fail("fields.%s.path: %w", f.name, err)
+		}
+		switch f.field.Type {
+		case "", typeAuto, typeString, typeNumber:
+		default:
+			fail("fields.%s.type: unknown type %q, want string, number or auto", f.name, f.field.Type)
+		}
+	}
+
+	if m.Where.Path != "" {
+		if err := validatePath(m.Where.Path); err != nil {
+			fail("where.path: %w", err)
+		}
+	}
+
+	switch m.Symbol.Case {
+	case "", "upper", "lower":
+	default:
+		fail("symbol.case: unknown case %q, want upper or lower", m.Symbol.Case)
+	}
+
+	if m.Interval <= 0 {
+		fail("interval: must be positive")
+	}
+	if m.RateScale == 0 {
+		fail("rate_scale: must not be zero")
+	}
+	if m.OpenInterestInBase && m.Fields.Price.Path == "" {
+		fail("open_interest_in_base: needs fields.price")
+	}
+	switch m.NextFundingUnit {
+	case "ms", "s":
+	default:
+		fail("next_funding_unit: unknown unit %q, want ms or s", m.NextFundingUnit)
+	}
+
+	return errors.Join(errs...)
+}
+
+func validatePath(path string) error {
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			return fmt.Errorf("empty segment in %q", path)
+		}
+	}
+
+	return nil
+}
+
+func (t symbolTransform) apply(symbol string) string {
+	if t.Split != "" {
+		symbol, _, _ = strings.Cut(symbol, t.Split)
+	}
+	symbol = strings.TrimPrefix(symbol, t.TrimPrefix)
+	symbol = strings.TrimSuffix(symbol, t.TrimSuffix)
+
+	switch t.Case {
+	case "upper":
+		symbol = strings.ToUpper(symbol)
+	case "lower":
+		symbol = strings.ToLower(symbol)
+	}
+
+	return symbol
+}
+
+// lookup walks a dot path through decoded JSON, numeric keys index arrays.
+func lookup(value any, path string) (any, bool) {
+	if path == "" {
+		return value, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			value = node[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// raw returns the value at f as text for FieldParser. Missing and null
+// values give an empty string, values of the wrong type an error.
+func (f field) raw(row any) (string, error) {
+	if f.Path == "" {
+		return "", nil
+	}
+
+	value, ok := lookup(row, f.Path)
+	if !ok || value == nil {
+		return "", nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		if f.Type == typeNumber {
+			return v, fmt.Errorf("expected number, got string")
+		}
+		return v, nil
+	case json.Number:
+		if f.Type == typeString {
+			return v.String(), fmt.Errorf("expected string, got number")
+		}
+		return v.String(), nil
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded), fmt.Errorf("expected %s, got %T", f.expected(), v)
+	}
+}
+
+func (f field) expected() string {
+	if f.Type == "" || f.Type == typeAuto {
+		return "string or number"
+	}
+
+	return f.Type
+}
@@ -2,10 +2,7 @@ package lighter
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/fiensola/funding/internal/domain"
@@ -13,36 +10,51 @@ import (
 	"go.uber.org/zap"
 )
 
+// fundingInterval is the settlement period of lighter funding.
+const fundingInterval = time.Hour
+
+// feedVenue is how the funding feed names lighter's own markets.
+const feedVenue = "lighter"
+
+// feedInterval is the period the funding feed quotes every venue for.
+const feedInterval = 8 * time.Hour
+
 type Client struct {
 	config     exchange.Config
-	httpClient *http.Client
+	options    options
+	httpClient *exchange.HTTPClient
 	logger     *zap.Logger
 }
 
-func NewClient(config exchange.Config, logger *zap.Logger) *Client {
-	transport := &http.Transport{}
+type options struct {
+	// IncludeForeign keeps the feed's rows about other venues as a
+	// secondary source for them.
+	IncludeForeign bool `mapstructure:"include_foreign"`
+}
 
-	if config.Proxy != "" {
-		proxyUrl, err := url.Parse(config.Proxy)
-		if err == nil && proxyUrl != nil {
-			transport.Proxy = http.ProxyURL(proxyUrl)
-		}
-	}
+func init() {
+	exchange.Register("lighter", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger)
+	})
+}
 
-	httpClient := &http.Client{
-		Timeout:   5 * time.Second,
-		Transport: transport,
+func NewClient(config exchange.Config, logger *zap.Logger) (*Client, error) {
+	var options options
+	if err := config.DecodeOptions(&options); err != nil {
+		return nil, err
 	}
 
 	return &Client{
 		config:     config,
-		httpClient: httpClient,
+		options:    options,
+		httpClient: exchange.NewHTTPClient(config, logger),
 		logger:     logger,
-	}
+	}, nil
 }
 
+// Name returns the name of the config block, rows are stored under it.
 func (c *Client) Name() string {
-	return "lighter"
+	return c.config.Name
 }
 
 type fundingResponse struct {
@@ -54,49 +66,98 @@ type fundingResponse struct {
 	Code int `json:"code"`
 }
 
+type orderBooksResponse struct {
+	Data []struct {
+		Symbol       string  `json:"symbol"`
+		LastPrice    float64 `json:"last_trade_price"`
+		OpenInterest float64 `json:"open_interest"` // base currency
+		Volume       float64 `json:"daily_quote_token_volume"`
+	} `json:"order_book_details"`
+	Code int `json:"code"`
+}
+
 func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
 	url := fmt.Sprintf("%s/v1/funding-rates", c.config.BaseURL)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
 	var fundingResp fundingResponse
-	if err := json.NewDecoder(resp.Body).Decode(&fundingResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := c.httpClient.GetJSON(ctx, url, &fundingResp); err != nil {
+		return nil, err
 	}
 
+	markets := c.getMarkets(ctx)
+
 	now := time.Now()
 	rates := make([]domain.FundingRate, 0)
 
+	foreign := 0
 	for _, item := range fundingResp.Data {
-		if item.Exchange == c.Name() {
+		if item.Exchange == feedVenue {
+			// the feed quotes every venue as an 8h rate
+			rates = append(rates, domain.FundingRate{
+				Exchange:        c.Name(),
+				VenueSymbol:     item.Symbol,
+				Rate:            item.Rate * fundingInterval.Hours() / feedInterval.Hours(),
+				FundingInterval: fundingInterval,
+				Price:           markets[item.Symbol].price,
+				Timestamp:       now,
+				NextFunding:     exchange.NextSettlement(now, fundingInterval),
+				Market:          markets[item.Symbol].snapshot,
+			})
+		} else if c.options.IncludeForeign && item.Exchange != "" {
+			// the venue's own schedule is unknown, so the rate keeps the
+			// feed's 8h period and has no next funding time
 			rates = append(rates, domain.FundingRate{
-				Exchange:  c.Name(),
-				Symbol:    item.Symbol,
-				Rate:      item.Rate / 8,
-				Timestamp: now,
+				Exchange:        item.Exchange,
+				Source:          c.Name(),
+				VenueSymbol:     item.Symbol,
+				Rate:            item.Rate,
+				FundingInterval: feedInterval,
+				Timestamp:       now,
 			})
+			foreign++
 		}
 	}
 
 	c.logger.Info("fetched funding rates",
 		zap.String("exchange", c.Name()),
 		zap.Int("count", len(rates)),
+		zap.Int("foreign", foreign),
 	)
 
 	return rates, nil
 }
+
+type market struct {
+	price    *float64
+	snapshot *domain.MarketSnapshot
+}
+
+// getMarkets returns the last trade price and market context per symbol,
+// the funding feed has neither. They only add context, so failures are
+// logged and the rates are stored without them.
+func (c *Client) getMarkets(ctx context.Context) map[string]market {
+	url := fmt.Sprintf("%s/v1/orderBookDetails", c.config.BaseURL)
+
+	var booksResp orderBooksResponse
+	if err := c.httpClient.GetJSON(ctx, url, &booksResp); err != nil {
+		c.logger.Warn("failed to fetch order book details", zap.String("exchange", c.Name()), zap.Error(err))
+	}
+
+	markets := make(map[string]market, len(booksResp.Data))
+	for _, book := range booksResp.Data {
+		// markets without trades report a zero price, their open interest
+		// has no notional then but the quote volume is still known
+		var price *float64
+		if book.LastPrice > 0 {
+			price = &book.LastPrice
+		}
+		openInterest := book.OpenInterest
+		volume := book.Volume
+		markets[book.Symbol] = market{
+			price:    price,
+			snapshot: exchange.Snapshot(exchange.Notional(&openInterest, price), &volume, nil, nil),
+		}
+	}
+
+	return markets
+}
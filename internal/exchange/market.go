@@ -0,0 +1,30 @@
+package exchange
+
+import "github.com/fiensola/funding/internal/domain"
+
+// Notional converts a base currency quantity to quote currency, it returns
+// nil when either value is unknown.
+func Notional(quantity, price *float64) *float64 {
+	if quantity == nil || price == nil {
+		return nil
+	}
+
+	notional := *quantity * *price
+
+	return &notional
+}
+
+// Snapshot builds a market snapshot, it returns nil when every field is
+// unknown so no empty rows get stored.
+func Snapshot(openInterest, volume24h, markPrice, indexPrice *float64) *domain.MarketSnapshot {
+	if openInterest == nil && volume24h == nil && markPrice == nil && indexPrice == nil {
+		return nil
+	}
+
+	return &domain.MarketSnapshot{
+		OpenInterest: openInterest,
+		Volume24h:    volume24h,
+		MarkPrice:    markPrice,
+		IndexPrice:   indexPrice,
+	}
+}
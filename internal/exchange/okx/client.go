@@ -0,0 +1,259 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+// fundingInterval is the default settlement period of okx funding, the
+// actual one is the gap between fundingTime and nextFundingTime.
+const fundingInterval = 8 * time.Hour
+
+type Client struct {
+	config     exchange.Config
+	options    options
+	httpClient *exchange.HTTPClient
+	// fundingLimiter keeps the per instrument requests within the budget of
+	// the funding-rate endpoint, separate from the host wide limiter.
+	fundingLimiter *exchange.RateLimiter
+	logger         *zap.Logger
+}
+
+type options struct {
+	// Concurrency limits the per instrument requests in flight.
+	Concurrency int `mapstructure:"concurrency"`
+	// RateLimit is the funding-rate request budget per second.
+	RateLimit float64 `mapstructure:"rate_limit"`
+	Burst     int     `mapstructure:"burst"`
+	// SettleCurrency picks one swap per asset, okx lists USDT, USDC and coin
+	// margined swaps side by side.
+	SettleCurrency string `mapstructure:"settle_currency"`
+}
+
+func init() {
+	exchange.Register("okx", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger)
+	})
+}
+
+func NewClient(config exchange.Config, logger *zap.Logger) (*Client, error) {
+	// okx allows 10 funding-rate requests per 2 seconds
+	options := options{
+		Concurrency:    4,
+		RateLimit:      5,
+		Burst:          5,
+		SettleCurrency: "USDT",
+	}
+	if err := config.DecodeOptions(&options); err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		config:         config,
+		options:        options,
+		httpClient:     exchange.NewHTTPClient(config, logger),
+		fundingLimiter: exchange.NewRateLimiter(options.RateLimit, options.Burst),
+		logger:         logger,
+	}, nil
+}
+
+// Name returns the name of the config block, rows are stored under it.
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+// okx reports api errors with a 200 status and a non zero code
+type envelope struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (e envelope) err() error {
+	if e.Code != "0" {
+		return fmt.Errorf("unexpected response code %s: %s", e.Code, e.Msg)
+	}
+
+	return nil
+}
+
+type instrumentsResponse struct {
+	envelope
+	Data []struct {
+		InstID    string `json:"instId"`
+		State     string `json:"state"`
+		SettleCcy string `json:"settleCcy"`
+	} `json:"data"`
+}
+
+type fundingResponse struct {
+	envelope
+	Data []struct {
+		InstID      string `json:"instId"`
+		Rate        string `json:"fundingRate"`
+		FundingTime string `json:"fundingTime"`     // ms timestamp of the upcoming settlement
+		NextFunding string `json:"nextFundingTime"` // ms timestamp of the one after
+	} `json:"data"`
+}
+
+type markPricesResponse struct {
+	envelope
+	Data []struct {
+		InstID    string `json:"instId"`
+		MarkPrice string `json:"markPx"`
+	} `json:"data"`
+}
+
+type openInterestResponse struct {
+	envelope
+	Data []struct {
+		InstID       string `json:"instId"`
+		OpenInterest string `json:"oiUsd"`
+	} `json:"data"`
+}
+
+type marketStats struct {
+	price        *float64
+	openInterest *float64
+}
+
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	instruments, err := c.getInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := c.getMarketStats(ctx)
+
+	// retries are charged to the funding-rate budget too
+	fundingCtx := exchange.WithLimiter(ctx, c.fundingLimiter)
+
+	rates, err := exchange.FanOut(fundingCtx, c.Name(), instruments, c.options.Concurrency,
+		func(ctx context.Context, instID string) ([]domain.FundingRate, error) {
+			fundingUrl := fmt.Sprintf("%s/api/v5/public/funding-rate?instId=%s", c.config.BaseURL, url.QueryEscape(instID))
+
+			var fundingResp fundingResponse
+			if err := c.httpClient.GetJSON(ctx, fundingUrl, &fundingResp); err != nil {
+				return nil, err
+			}
+			if err := fundingResp.err(); err != nil {
+				return nil, err
+			}
+			if len(fundingResp.Data) == 0 {
+				return nil, fmt.Errorf("no funding rate for %s", instID)
+			}
+			item := fundingResp.Data[0]
+
+			var parser exchange.FieldParser
+			rate := parser.Float("fundingRate", item.Rate)
+
+			now := time.Now()
+			nextFunding := unixMilli(item.FundingTime)
+			interval := fundingInterval
+			if after := unixMilli(item.NextFunding); nextFunding != nil && after != nil && after.After(*nextFunding) {
+				interval = after.Sub(*nextFunding)
+			}
+			if nextFunding == nil {
+				nextFunding = exchange.NextSettlement(now, interval)
+			}
+
+			stat := stats[instID]
+			return []domain.FundingRate{{
+				Exchange:        c.Name(),
+				VenueSymbol:     instID,
+				Rate:            rate,
+				FundingInterval: interval,
+				Price:           stat.price,
+				Timestamp:       now,
+				NextFunding:     nextFunding,
+				Market:          exchange.Snapshot(stat.openInterest, nil, stat.price, nil),
+				Issues:          parser.Issues(),
+			}}, nil
+		},
+	)
+
+	c.logger.Info("fetched funding rates",
+		zap.String("exchange", c.Name()),
+		zap.Int("count", len(rates)),
+	)
+
+	return rates, err
+}
+
+// getInstruments returns the live swaps settled in SettleCurrency.
+func (c *Client) getInstruments(ctx context.Context) ([]string, error) {
+	instrumentsUrl := fmt.Sprintf("%s/api/v5/public/instruments?instType=SWAP", c.config.BaseURL)
+
+	var instrumentsResp instrumentsResponse
+	if err := c.httpClient.GetJSON(ctx, instrumentsUrl, &instrumentsResp); err != nil {
+		return nil, err
+	}
+	if err := instrumentsResp.err(); err != nil {
+		return nil, err
+	}
+
+	instruments := make([]string, 0, len(instrumentsResp.Data))
+	for _, item := range instrumentsResp.Data {
+		if item.State == "live" && item.SettleCcy == c.options.SettleCurrency {
+			instruments = append(instruments, item.InstID)
+		}
+	}
+	sort.Strings(instruments)
+
+	return instruments, nil
+}
+
+// getMarketStats returns mark price and open interest per instrument, both
+// served for every swap in one request. They only add context, so failures
+// are logged and the rates are stored without them.
+func (c *Client) getMarketStats(ctx context.Context) map[string]marketStats {
+	stats := make(map[string]marketStats)
+
+	var pricesResp markPricesResponse
+	if err := c.httpClient.GetJSON(ctx, fmt.Sprintf("%s/api/v5/public/mark-price?instType=SWAP", c.config.BaseURL), &pricesResp); err != nil {
+		c.logger.Warn("failed to fetch mark prices", zap.String("exchange", c.Name()), zap.Error(err))
+	}
+
+	var oiResp openInterestResponse
+	if err := c.httpClient.GetJSON(ctx, fmt.Sprintf("%s/api/v5/public/open-interest?instType=SWAP", c.config.BaseURL), &oiResp); err != nil {
+		c.logger.Warn("failed to fetch open interest", zap.String("exchange", c.Name()), zap.Error(err))
+	}
+
+	var parser exchange.FieldParser
+	for _, item := range pricesResp.Data {
+		stat := stats[item.InstID]
+		stat.price = parser.OptionalFloat("markPx", item.MarkPrice)
+		stats[item.InstID] = stat
+	}
+	for _, item := range oiResp.Data {
+		stat := stats[item.InstID]
+		stat.openInterest = parser.OptionalFloat("oiUsd", item.OpenInterest)
+		stats[item.InstID] = stat
+	}
+
+	if issues := parser.Issues(); len(issues) > 0 {
+		c.logger.Warn("ignored malformed market stats",
+			zap.String("exchange", c.Name()),
+			zap.Int("count", len(issues)),
+		)
+	}
+
+	return stats
+}
+
+// unixMilli parses the string ms timestamps okx uses, nil when unset.
+func unixMilli(value string) *time.Time {
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	return exchange.UnixMilli(ts)
+}
@@ -2,11 +2,7 @@ package pacifica
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
 	"time"
 
 	"github.com/fiensola/funding/internal/domain"
@@ -14,43 +10,42 @@ import (
 	"go.uber.org/zap"
 )
 
+// fundingInterval is the settlement period of pacifica funding.
+const fundingInterval = time.Hour
+
 type Client struct {
 	config     exchange.Config
-	httpClient *http.Client
+	httpClient *exchange.HTTPClient
 	logger     *zap.Logger
 }
 
-func NewClient(config exchange.Config, logger *zap.Logger) *Client {
-	transport := &http.Transport{}
-
-	if config.Proxy != "" {
-		proxyUrl, err := url.Parse(config.Proxy)
-		if err == nil && proxyUrl != nil {
-			transport.Proxy = http.ProxyURL(proxyUrl)
-		}
-	}
-
-	httpClient := &http.Client{
-		Timeout:   5 * time.Second,
-		Transport: transport,
-	}
+func init() {
+	exchange.Register("pacifica", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger), nil
+	})
+}
 
+func NewClient(config exchange.Config, logger *zap.Logger) *Client {
 	return &Client{
 		config:     config,
-		httpClient: httpClient,
+		httpClient: exchange.NewHTTPClient(config, logger),
 		logger:     logger,
 	}
 }
 
+// Name returns the name of the config block, rows are stored under it.
 func (c *Client) Name() string {
-	return "pacifica"
+	return c.config.Name
 }
 
 type fundingResponse struct {
 	Data []struct {
-		Rate   string `json:"funding"`
-		Price  string `json:"oracle"`
-		Symbol string `json:"symbol"`
+		Rate         string `json:"funding"`
+		Price        string `json:"oracle"`
+		Mark         string `json:"mark"`
+		OpenInterest string `json:"open_interest"` // base currency
+		Volume       string `json:"volume_24h"`
+		Symbol       string `json:"symbol"`
 	} `json:"data"`
 	Success bool `json:"success"`
 }
@@ -58,40 +53,31 @@ type fundingResponse struct {
 func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
 	url := fmt.Sprintf("%s/v1/info/prices", c.config.BaseURL)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
 	var fundingResp fundingResponse
-	if err := json.NewDecoder(resp.Body).Decode(&fundingResp); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := c.httpClient.GetJSON(ctx, url, &fundingResp); err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
 	rates := make([]domain.FundingRate, 0, len(fundingResp.Data))
 
+	var parser exchange.FieldParser
 	for _, item := range fundingResp.Data {
-		rate, _ := strconv.ParseFloat(item.Rate, 64)
-		price, _ := strconv.ParseFloat(item.Price, 64)
+		rate := parser.Float("funding", item.Rate)
+		price := parser.Float("oracle", item.Price)
+		mark := parser.OptionalFloat("mark", item.Mark)
+		openInterest := parser.OptionalFloat("open_interest", item.OpenInterest)
+		volume := parser.OptionalFloat("volume_24h", item.Volume)
 		rates = append(rates, domain.FundingRate{
-			Exchange:  c.Name(),
-			Price:     &price,
-			Symbol:    item.Symbol,
-			Rate:      rate,
-			Timestamp: now,
+			Exchange:        c.Name(),
+			Price:           &price,
+			VenueSymbol:     item.Symbol,
+			Rate:            rate,
+			FundingInterval: fundingInterval,
+			Timestamp:       now,
+			NextFunding:     exchange.NextSettlement(now, fundingInterval),
+			Market:          exchange.Snapshot(exchange.Notional(openInterest, mark), volume, mark, &price),
+			Issues:          parser.Issues(),
 		})
 	}
 
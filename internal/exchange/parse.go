@@ -0,0 +1,71 @@
+package exchange
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+// FieldParser parses the string fields of one venue row and collects the
+// failures, so broken values are quarantined instead of becoming zeros.
+type FieldParser struct {
+	issues []domain.FieldIssue
+}
+
+// Float parses a required number.
+func (p *FieldParser) Float(field, raw string) float64 {
+	value, ok := p.parse(field, raw)
+	if !ok {
+		return 0
+	}
+
+	return value
+}
+
+// OptionalFloat parses a number that venues may omit, empty input gives nil.
+func (p *FieldParser) OptionalFloat(field, raw string) *float64 {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	value, ok := p.parse(field, raw)
+	if !ok {
+		return nil
+	}
+
+	return &value
+}
+
+func (p *FieldParser) parse(field, raw string) (float64, bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		p.Fail(field, raw, "empty value")
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		p.Fail(field, raw, "not a number")
+		return 0, false
+	}
+
+	return value, true
+}
+
+// Fail records an issue with field.
+func (p *FieldParser) Fail(field, raw, reason string) {
+	p.issues = append(p.issues, domain.FieldIssue{
+		Field:  field,
+		Raw:    raw,
+		Reason: reason,
+	})
+}
+
+// Issues returns the collected issues and resets the parser for the next row.
+func (p *FieldParser) Issues() []domain.FieldIssue {
+	issues := p.issues
+	p.issues = nil
+
+	return issues
+}
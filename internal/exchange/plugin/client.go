@@ -0,0 +1,154 @@
+// Package plugin runs exchange adapters as separate processes that speak
+// the gRPC contract in proto/plugin/v1. The tracker side is the "plugin"
+// adapter, Serve is the plugin side for adapters written in Go.
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	pluginv1 "github.com/fiensola/funding/proto/plugin/v1"
+	"go.uber.org/zap"
+)
+
+type Client struct {
+	name       string
+	supervisor *supervisor
+	logger     *zap.Logger
+}
+
+// historyClient is returned for plugins that report CAPABILITY_HISTORY.
+type historyClient struct {
+	*Client
+}
+
+type options struct {
+	Command string   `mapstructure:"command"`
+	Args    []string `mapstructure:"args"`
+	// Env holds KEY=value entries added to the environment of the
+	// process. It is a list because viper lowercases map keys.
+	Env []string `mapstructure:"env"`
+	// StartTimeout bounds the wait for a started process to answer.
+	StartTimeout time.Duration `mapstructure:"start_timeout"`
+	CallTimeout  time.Duration `mapstructure:"call_timeout"`
+	// MaxTimeouts is how many calls in a row may time out before the
+	// process is considered hung and restarted.
+	MaxTimeouts     int           `mapstructure:"max_timeouts"`
+	RestartDelay    time.Duration `mapstructure:"restart_delay"`
+	MaxRestartDelay time.Duration `mapstructure:"max_restart_delay"`
+}
+
+func init() {
+	exchange.Register("plugin", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		client, err := NewClient(config, logger)
+		if err != nil {
+			return nil, err
+		}
+		if client.supervisor.hasCapability(pluginv1.Capability_CAPABILITY_HISTORY) {
+			return historyClient{client}, nil
+		}
+		return client, nil
+	})
+}
+
+// NewClient starts the plugin process and keeps it running until Close.
+func NewClient(config exchange.Config, logger *zap.Logger) (*Client, error) {
+	options := options{
+		StartTimeout:    10 * time.Second,
+		CallTimeout:     30 * time.Second,
+		MaxTimeouts:     3,
+		RestartDelay:    time.Second,
+		MaxRestartDelay: time.Minute,
+	}
+	if err := config.DecodeOptions(&options); err != nil {
+		return nil, err
+	}
+	if options.Command == "" {
+		return nil, fmt.Errorf("command: required")
+	}
+	for i, entry := range options.Env {
+		if key, _, ok := strings.Cut(entry, "="); !ok || key == "" {
+			return nil, fmt.Errorf("env[%d]: %q is not KEY=value", i, entry)
+		}
+	}
+
+	logger = logger.With(zap.String("plugin", config.Name))
+
+	supervisor, err := newSupervisor(options, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		name:       config.Name,
+		supervisor: supervisor,
+		logger:     logger,
+	}, nil
+}
+
+// Name returns the name of the config block, rows are stored under it.
+func (c *Client) Name() string {
+	return c.name
+}
+
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	var resp *pluginv1.FetchFundingRatesResponse
+	err := c.supervisor.call(ctx, func(ctx context.Context, client pluginv1.ExchangePluginClient) error {
+		var err error
+		resp, err = client.FetchFundingRates(ctx, &pluginv1.FetchFundingRatesRequest{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rates := decodeRates(resp.GetRates(), c.Name())
+
+	c.logger.Info("fetched funding rates",
+		zap.String("exchange", c.Name()),
+		zap.Int("count", len(rates)),
+	)
+
+	// the plugin fetched part of its rates, the tracker stores them
+	if failures := resp.GetFailures(); len(failures) > 0 {
+		partial := &exchange.PartialError{
+			Exchange: c.Name(),
+			Total:    int(resp.GetTotalRequests()),
+			Failures: make(map[string]error, len(failures)),
+		}
+		for _, failure := range failures {
+			partial.Failures[failure.GetKey()] = errors.New(failure.GetError())
+		}
+
+		return rates, partial
+	}
+
+	return rates, nil
+}
+
+func (c historyClient) FetchFundingHistory(ctx context.Context, symbol string, from time.Time) ([]domain.FundingRate, error) {
+	var resp *pluginv1.FetchFundingHistoryResponse
+	err := c.supervisor.call(ctx, func(ctx context.Context, client pluginv1.ExchangePluginClient) error {
+		var err error
+		resp, err = client.FetchFundingHistory(ctx, &pluginv1.FetchFundingHistoryRequest{
+			Symbol:     symbol,
+			FromUnixMs: from.UnixMilli(),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRates(resp.GetRates(), c.Name()), nil
+}
+
+// Close terminates the plugin process.
+func (c *Client) Close() error {
+	return c.supervisor.close()
+}
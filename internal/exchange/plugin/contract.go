@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	pluginv1 "github.com/fiensola/funding/proto/plugin/v1"
+)
+
+// ProtocolVersion is the contract version in proto/plugin/v1/plugin.proto.
+const ProtocolVersion = 1
+
+// encodeRates converts rates to their contract messages.
+func encodeRates(rates []domain.FundingRate) []*pluginv1.FundingRate {
+	items := make([]*pluginv1.FundingRate, 0, len(rates))
+	for _, rate := range rates {
+		item := &pluginv1.FundingRate{
+			Exchange:               rate.Exchange,
+			VenueSymbol:            rate.VenueSymbol,
+			Rate:                   rate.Rate,
+			FundingIntervalSeconds: int64(rate.FundingInterval / time.Second),
+			TimestampUnixMs:        rate.Timestamp.UnixMilli(),
+			Price:                  rate.Price,
+			LongRate:               rate.LongRate,
+			ShortRate:              rate.ShortRate,
+			LongBorrowRate:         rate.LongBorrowRate,
+			ShortBorrowRate:        rate.ShortBorrowRate,
+		}
+		if rate.NextFunding != nil {
+			next := rate.NextFunding.UnixMilli()
+			item.NextFundingUnixMs = &next
+		}
+		if market := rate.Market; market != nil {
+			item.OpenInterest = market.OpenInterest
+			item.Volume_24H = market.Volume24h
+			item.MarkPrice = market.MarkPrice
+			item.IndexPrice = market.IndexPrice
+		}
+
+		for _, issue := range rate.Issues {
+			item.Issues = append(item.Issues, &pluginv1.FieldIssue{
+				Field:  issue.Field,
+				Raw:    issue.Raw,
+				Reason: issue.Reason,
+			})
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// decodeRates converts contract messages to rates. Rows without an
+// exchange describe the plugin's own venue.
+func decodeRates(items []*pluginv1.FundingRate, name string) []domain.FundingRate {
+	rates := make([]domain.FundingRate, 0, len(items))
+	for _, item := range items {
+		rate := domain.FundingRate{
+			Exchange:        item.GetExchange(),
+			VenueSymbol:     item.GetVenueSymbol(),
+			Rate:            item.GetRate(),
+			FundingInterval: time.Duration(item.GetFundingIntervalSeconds()) * time.Second,
+			Price:           item.Price,
+			Timestamp:       time.UnixMilli(item.GetTimestampUnixMs()),
+			LongRate:        item.LongRate,
+			ShortRate:       item.ShortRate,
+			LongBorrowRate:  item.LongBorrowRate,
+			ShortBorrowRate: item.ShortBorrowRate,
+		}
+		if rate.Exchange == "" {
+			rate.Exchange = name
+		} else if rate.Exchange != name {
+			rate.Source = name
+		}
+		if item.NextFundingUnixMs != nil {
+			next := time.UnixMilli(item.GetNextFundingUnixMs())
+			rate.NextFunding = &next
+		}
+
+		rate.Market = exchange.Snapshot(item.OpenInterest, item.Volume_24H, item.MarkPrice, item.IndexPrice)
+
+		for _, issue := range item.GetIssues() {
+			rate.Issues = append(rate.Issues, domain.FieldIssue{
+				Field:  issue.GetField(),
+				Raw:    issue.GetRaw(),
+				Reason: issue.GetReason(),
+			})
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return rates
+}
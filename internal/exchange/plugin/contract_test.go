@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	pluginv1 "github.com/fiensola/funding/proto/plugin/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// TestRatesRoundTrip sends rates through the wire format and back.
+func TestRatesRoundTrip(t *testing.T) {
+	ts := time.UnixMilli(1767225600000)
+	next := ts.Add(time.Hour)
+
+	rates := []domain.FundingRate{
+		{
+			Exchange:        "helper",
+			VenueSymbol:     "BTC-PERP",
+			Rate:            0.0001,
+			FundingInterval: time.Hour,
+			Price:           ptr(65000.0),
+			Timestamp:       ts,
+			NextFunding:     &next,
+			LongRate:        ptr(0.0002),
+			ShortRate:       ptr(-0.0001),
+			Market:          &domain.MarketSnapshot{OpenInterest: ptr(5e8), MarkPrice: ptr(65001.0)},
+		},
+		{
+			// a row about another venue keeps its exchange and is sourced
+			// from the plugin
+			Exchange:        "binance",
+			VenueSymbol:     "ETHUSDT",
+			Rate:            0,
+			FundingInterval: 8 * time.Hour,
+			Timestamp:       ts,
+			Issues:          []domain.FieldIssue{{Field: "rate", Raw: "abc", Reason: "not a number"}},
+		},
+	}
+
+	data, err := proto.Marshal(&pluginv1.FetchFundingRatesResponse{Rates: encodeRates(rates)})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var resp pluginv1.FetchFundingRatesResponse
+	if err := proto.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	got := decodeRates(resp.GetRates(), "helper")
+
+	want := rates
+	want[1].Source = "helper"
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded rates differ\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestDecodeRatesDefaultsToPluginExchange(t *testing.T) {
+	got := decodeRates([]*pluginv1.FundingRate{{VenueSymbol: "BTC-PERP"}}, "helper")
+
+	if len(got) != 1 || got[0].Exchange != "helper" || got[0].Source != "" {
+		t.Fatalf("rates = %+v, want one helper row without a source", got)
+	}
+	if got[0].NextFunding != nil || got[0].Price != nil || got[0].Market != nil {
+		t.Errorf("unset optional fields decoded as %+v", got[0])
+	}
+}
@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fiensola/funding/internal/exchange"
+	pluginv1 "github.com/fiensola/funding/proto/plugin/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AddrEnv holds the unix socket a plugin process must serve on.
+const AddrEnv = "FUNDING_PLUGIN_ADDR"
+
+// server exposes an exchange over the plugin contract.
+type server struct {
+	pluginv1.UnimplementedExchangePluginServer
+
+	ex exchange.Exchange
+}
+
+// Serve runs ex as a plugin on the socket named by FUNDING_PLUGIN_ADDR until
+// the process receives SIGTERM or SIGINT. It is the Go side of the contract
+// in proto/plugin/v1, see cmd/sampleplugin.
+func Serve(ex exchange.Exchange) error {
+	addr := os.Getenv(AddrEnv)
+	if addr == "" {
+		return fmt.Errorf("%s is not set, plugins are started by the tracker", AddrEnv)
+	}
+
+	listener, err := net.Listen("unix", addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	srv := grpc.NewServer()
+	pluginv1.RegisterExchangePluginServer(srv, &server{ex: ex})
+
+	stopCh := make(chan os.Signal, 1)
+	signal.Notify(stopCh, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-stopCh
+		// give in-flight fetches a moment, the tracker kills us anyway
+		timer := time.AfterFunc(5*time.Second, srv.Stop)
+		srv.GracefulStop()
+		timer.Stop()
+	}()
+
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	return nil
+}
+
+func (s *server) Describe(ctx context.Context, req *pluginv1.DescribeRequest) (*pluginv1.DescribeResponse, error) {
+	resp := &pluginv1.DescribeResponse{
+		Name:            s.ex.Name(),
+		ProtocolVersion: ProtocolVersion,
+	}
+	if _, ok := s.ex.(exchange.HistoryExchange); ok {
+		resp.Capabilities = append(resp.Capabilities, pluginv1.Capability_CAPABILITY_HISTORY)
+	}
+
+	return resp, nil
+}
+
+func (s *server) FetchFundingRates(ctx context.Context, req *pluginv1.FetchFundingRatesRequest) (*pluginv1.FetchFundingRatesResponse, error) {
+	rates, err := s.ex.FetchFundingRates(ctx)
+	resp := &pluginv1.FetchFundingRatesResponse{}
+
+	// a partial fetch still returns the rates it got
+	var partial *exchange.PartialError
+	if errors.As(err, &partial) && !partial.AllFailed() {
+		resp.TotalRequests = uint32(partial.Total)
+		for key, err := range partial.Failures {
+			resp.Failures = append(resp.Failures, &pluginv1.FetchFailure{Key: key, Error: err.Error()})
+		}
+	} else if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	resp.Rates = encodeRates(rates)
+
+	return resp, nil
+}
+
+func (s *server) FetchFundingHistory(ctx context.Context, req *pluginv1.FetchFundingHistoryRequest) (*pluginv1.FetchFundingHistoryResponse, error) {
+	history, ok := s.ex.(exchange.HistoryExchange)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "history is not supported")
+	}
+
+	rates, err := history.FetchFundingHistory(ctx, req.GetSymbol(), time.UnixMilli(req.GetFromUnixMs()))
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &pluginv1.FetchFundingHistoryResponse{Rates: encodeRates(rates)}, nil
+}
@@ -0,0 +1,302 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	pluginv1 "github.com/fiensola/funding/proto/plugin/v1"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapio"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+var errNotRunning = errors.New("plugin process is not running")
+
+// stopTimeout is how long a plugin may take to exit after SIGTERM.
+const stopTimeout = 5 * time.Second
+
+// supervisor keeps one plugin process running. It restarts the process
+// with backoff when it exits and kills it when calls keep timing out.
+type supervisor struct {
+	options options
+	logger  *zap.Logger
+	dir     string
+
+	mu           sync.Mutex
+	cmd          *exec.Cmd
+	conn         *grpc.ClientConn
+	client       pluginv1.ExchangePluginClient
+	exited       chan struct{}
+	startedAt    time.Time
+	capabilities []pluginv1.Capability
+	timeouts     int
+
+	closing chan struct{}
+	done    chan struct{}
+}
+
+func newSupervisor(options options, logger *zap.Logger) (*supervisor, error) {
+	dir, err := os.MkdirTemp("", "funding-plugin-")
+	if err != nil {
+		return nil, fmt.Errorf("create socket dir: %w", err)
+	}
+
+	s := &supervisor{
+		options: options,
+		logger:  logger,
+		dir:     dir,
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if err := s.start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	go s.supervise()
+
+	return s, nil
+}
+
+// start launches the process and waits until it answers Describe.
+func (s *supervisor) start() error {
+	socket := filepath.Join(s.dir, "plugin.sock")
+	os.Remove(socket)
+
+	output := &zapio.Writer{Log: s.logger, Level: zap.InfoLevel}
+	cmd := exec.Command(s.options.Command, s.options.Args...)
+	cmd.Env = append(os.Environ(), AddrEnv+"="+socket)
+	cmd.Env = append(cmd.Env, s.options.Env...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start plugin: %w", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		output.Close()
+		select {
+		case <-s.closing:
+			s.logger.Info("plugin process stopped", zap.Int("pid", cmd.Process.Pid))
+		default:
+			s.logger.Warn("plugin process exited", zap.Int("pid", cmd.Process.Pid), zap.Error(err))
+		}
+		close(exited)
+	}()
+
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.kill(cmd, exited)
+		return fmt.Errorf("dial plugin: %w", err)
+	}
+
+	client := pluginv1.NewExchangePluginClient(conn)
+	capabilities, err := s.describe(client, socket, exited)
+	if err != nil {
+		conn.Close()
+		s.kill(cmd, exited)
+		return err
+	}
+
+	s.mu.Lock()
+	s.cmd = cmd
+	s.conn = conn
+	s.client = client
+	s.exited = exited
+	s.startedAt = time.Now()
+	s.capabilities = capabilities
+	s.timeouts = 0
+	s.mu.Unlock()
+
+	s.logger.Info("plugin started", zap.Int("pid", cmd.Process.Pid))
+
+	return nil
+}
+
+// describe waits for the plugin to listen and checks the protocol version.
+func (s *supervisor) describe(client pluginv1.ExchangePluginClient, socket string, exited <-chan struct{}) ([]pluginv1.Capability, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.options.StartTimeout)
+	defer cancel()
+
+	// stop waiting when the process dies before listening
+	go func() {
+		select {
+		case <-exited:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// a dial before the socket exists fails and is retried with a second
+	// of backoff
+	if err := waitForSocket(ctx, socket); err != nil {
+		return nil, fmt.Errorf("wait for plugin socket: %w", err)
+	}
+
+	resp, err := client.Describe(ctx, &pluginv1.DescribeRequest{ProtocolVersion: ProtocolVersion}, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, fmt.Errorf("describe plugin: %w", err)
+	}
+
+	if version := resp.GetProtocolVersion(); version != ProtocolVersion {
+		return nil, fmt.Errorf("plugin speaks protocol %d, want %d", version, ProtocolVersion)
+	}
+
+	return resp.GetCapabilities(), nil
+}
+
+// waitForSocket polls until the plugin created socket.
+func waitForSocket(ctx context.Context, socket string) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if _, err := os.Stat(socket); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// supervise restarts the process whenever it exits until close is called.
+// The backoff resets once a process stayed up longer than MaxRestartDelay.
+func (s *supervisor) supervise() {
+	defer close(s.done)
+
+	delay := s.options.RestartDelay
+	for {
+		s.mu.Lock()
+		exited, startedAt := s.exited, s.startedAt
+		s.mu.Unlock()
+
+		select {
+		case <-s.closing:
+			return
+		case <-exited:
+		}
+
+		s.mu.Lock()
+		s.conn.Close()
+		s.conn = nil
+		s.client = nil
+		s.mu.Unlock()
+
+		if time.Since(startedAt) > s.options.MaxRestartDelay {
+			delay = s.options.RestartDelay
+		}
+
+		for {
+			s.logger.Info("restarting plugin", zap.Duration("delay", delay))
+
+			select {
+			case <-s.closing:
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, s.options.MaxRestartDelay)
+
+			err := s.start()
+			if err == nil {
+				break
+			}
+			s.logger.Error("failed to restart plugin", zap.Error(err))
+		}
+	}
+}
+
+// call runs fn with the call timeout. Calls that hit the timeout
+// MaxTimeouts times in a row kill the process, which is restarted.
+func (s *supervisor) call(ctx context.Context, fn func(ctx context.Context, client pluginv1.ExchangePluginClient) error) error {
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+
+	if client == nil {
+		return errNotRunning
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, s.options.CallTimeout)
+	defer cancel()
+
+	err := fn(callCtx, client)
+	s.recordCall(ctx, err)
+
+	return err
+}
+
+func (s *supervisor) recordCall(ctx context.Context, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// only our own timeout counts, not the caller giving up
+	if status.Code(err) != codes.DeadlineExceeded || ctx.Err() != nil {
+		s.timeouts = 0
+		return
+	}
+
+	s.timeouts++
+	if s.timeouts < s.options.MaxTimeouts || s.cmd == nil {
+		return
+	}
+
+	s.logger.Warn("plugin keeps timing out, killing it", zap.Int("timeouts", s.timeouts))
+	s.timeouts = 0
+	s.cmd.Process.Kill()
+}
+
+func (s *supervisor) hasCapability(capability pluginv1.Capability) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Contains(s.capabilities, capability)
+}
+
+// kill stops a process that never became ready.
+func (s *supervisor) kill(cmd *exec.Cmd, exited <-chan struct{}) {
+	cmd.Process.Kill()
+	<-exited
+}
+
+// close stops supervising and terminates the process.
+func (s *supervisor) close() error {
+	close(s.closing)
+	<-s.done
+
+	s.mu.Lock()
+	cmd, conn, exited := s.cmd, s.conn, s.exited
+	s.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+
+	cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-exited:
+	case <-time.After(stopTimeout):
+		cmd.Process.Kill()
+		<-exited
+	}
+
+	return os.RemoveAll(s.dir)
+}
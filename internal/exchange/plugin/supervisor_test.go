@@ -0,0 +1,285 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	pluginv1 "github.com/fiensola/funding/proto/plugin/v1"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// helperEnv makes the test binary act as a plugin, the value picks how.
+const helperEnv = "FUNDING_PLUGIN_TEST_HELPER"
+
+func TestMain(m *testing.M) {
+	mode := os.Getenv(helperEnv)
+	if mode == "" {
+		os.Exit(m.Run())
+	}
+
+	if err := runHelper(mode); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// runHelper is the plugin side of the tests:
+//   - serve answers every call
+//   - crash serves and exits shortly after answering Describe
+//   - hang answers Describe but never finishes a fetch
+//   - partial fails to fetch one of two symbols
+//   - mute accepts connections and never answers
+func runHelper(mode string) error {
+	switch mode {
+	case "serve", "hang", "partial":
+		return Serve(helperExchange{hang: mode == "hang", partial: mode == "partial"})
+	case "crash":
+		listener, err := net.Listen("unix", os.Getenv(AddrEnv))
+		if err != nil {
+			return err
+		}
+		srv := grpc.NewServer()
+		pluginv1.RegisterExchangePluginServer(srv, crashingServer{&server{ex: helperExchange{}}})
+		return srv.Serve(listener)
+	case "mute":
+		listener, err := net.Listen("unix", os.Getenv(AddrEnv))
+		if err != nil {
+			return err
+		}
+		for {
+			if _, err := listener.Accept(); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown helper mode %q", mode)
+	}
+}
+
+type helperExchange struct {
+	hang    bool
+	partial bool
+}
+
+func (helperExchange) Name() string {
+	return "helper"
+}
+
+func (e helperExchange) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	if e.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	rates := []domain.FundingRate{{
+		VenueSymbol:     "BTC-PERP",
+		Rate:            0.0001,
+		FundingInterval: time.Hour,
+		Timestamp:       time.Now(),
+	}}
+	if e.partial {
+		return rates, &exchange.PartialError{
+			Exchange: e.Name(),
+			Total:    2,
+			Failures: map[string]error{"ETH-PERP": errors.New("rate limited")},
+		}
+	}
+
+	return rates, nil
+}
+
+// crashingServer exits soon after it was described, so the process was up
+// for a known time when the supervisor sees it exit.
+type crashingServer struct {
+	*server
+}
+
+func (s crashingServer) Describe(ctx context.Context, req *pluginv1.DescribeRequest) (*pluginv1.DescribeResponse, error) {
+	time.AfterFunc(20*time.Millisecond, func() { os.Exit(1) })
+
+	return s.server.Describe(ctx, req)
+}
+
+// newHelperClient starts the test binary as a plugin in mode.
+func newHelperClient(t *testing.T, mode string, options map[string]any) (*Client, *observer.ObservedLogs, error) {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	config := map[string]any{
+		"command":       os.Args[0],
+		"env":           []any{helperEnv + "=" + mode},
+		"start_timeout": "5s",
+	}
+	for key, value := range options {
+		config[key] = value
+	}
+
+	client, err := NewClient(exchange.Config{Name: "helper", Options: config}, zap.New(core))
+	if err == nil {
+		t.Cleanup(func() { client.Close() })
+	}
+
+	return client, logs, err
+}
+
+// waitFor polls until ok holds or fails the test after 5 seconds.
+func waitFor(t *testing.T, what string, ok func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !ok() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClientFetchesFromPlugin(t *testing.T) {
+	start := time.Now()
+	client, _, err := newHelperClient(t, "serve", nil)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	// the client dials once the socket exists, not into the gRPC backoff
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("NewClient took %v, want the plugin start only", elapsed)
+	}
+
+	rates, err := client.FetchFundingRates(context.Background())
+	if err != nil {
+		t.Fatalf("FetchFundingRates: %v", err)
+	}
+	if len(rates) != 1 || rates[0].Exchange != "helper" || rates[0].VenueSymbol != "BTC-PERP" {
+		t.Errorf("rates = %+v, want BTC-PERP on helper", rates)
+	}
+}
+
+func TestClientReturnsPartialFetch(t *testing.T) {
+	client, _, err := newHelperClient(t, "partial", nil)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	rates, err := client.FetchFundingRates(context.Background())
+
+	var partial *exchange.PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("err = %v, want a partial error", err)
+	}
+	if partial.Exchange != "helper" || partial.Total != 2 || len(partial.Failures) != 1 || partial.AllFailed() {
+		t.Errorf("partial = %+v, want 1 of 2 failed on helper", partial)
+	}
+	if failure := partial.Failures["ETH-PERP"]; failure == nil || failure.Error() != "rate limited" {
+		t.Errorf("ETH-PERP failure = %v, want rate limited", failure)
+	}
+	if len(rates) != 1 || rates[0].VenueSymbol != "BTC-PERP" {
+		t.Errorf("rates = %+v, want the fetched BTC-PERP", rates)
+	}
+}
+
+func TestNewClientRejectsMalformedEnv(t *testing.T) {
+	for _, entry := range []string{"NAME", "=value"} {
+		_, err := NewClient(exchange.Config{Name: "helper", Options: map[string]any{
+			"command": os.Args[0],
+			"env":     []any{entry},
+		}}, zap.NewNop())
+		if err == nil || !strings.Contains(err.Error(), "KEY=value") {
+			t.Errorf("env %q: err = %v, want KEY=value error", entry, err)
+		}
+	}
+}
+
+func TestStartFailsWhenDescribeNeverAnswers(t *testing.T) {
+	start := time.Now()
+	_, _, err := newHelperClient(t, "mute", map[string]any{"start_timeout": "200ms"})
+	if err == nil {
+		t.Fatal("NewClient succeeded, want a describe error")
+	}
+	if !strings.Contains(err.Error(), "describe plugin") || status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("err = %v, want describe deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("NewClient took %v, want about the start timeout", elapsed)
+	}
+}
+
+func TestSupervisorRestartsWithBackoff(t *testing.T) {
+	_, logs, err := newHelperClient(t, "crash", map[string]any{
+		"restart_delay":     "10ms",
+		"max_restart_delay": "100ms",
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	restarts := func() []observer.LoggedEntry {
+		return logs.FilterMessage("restarting plugin").AllUntimed()
+	}
+	waitFor(t, "5 restarts", func() bool { return len(restarts()) >= 5 })
+
+	// every process lives shorter than max_restart_delay, so the delay
+	// doubles up to the cap instead of resetting
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond, 100 * time.Millisecond}
+	for i, entry := range restarts()[:len(want)] {
+		if delay := entry.ContextMap()["delay"].(time.Duration); delay != want[i] {
+			t.Errorf("restart %d delay = %v, want %v", i, delay, want[i])
+		}
+	}
+}
+
+func TestSupervisorKillsPluginAfterMaxTimeouts(t *testing.T) {
+	client, logs, err := newHelperClient(t, "hang", map[string]any{
+		"call_timeout":  "50ms",
+		"max_timeouts":  2,
+		"restart_delay": "10ms",
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	client.supervisor.mu.Lock()
+	exited := client.supervisor.exited
+	client.supervisor.mu.Unlock()
+
+	for i := 1; i <= 2; i++ {
+		_, err := client.FetchFundingRates(context.Background())
+		if status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("fetch %d: err = %v, want deadline exceeded", i, err)
+		}
+
+		if i == 1 {
+			select {
+			case <-exited:
+				t.Fatal("plugin was killed after the first timeout")
+			default:
+			}
+		}
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("plugin was not killed after max_timeouts")
+	}
+	if n := logs.FilterMessage("plugin keeps timing out, killing it").Len(); n != 1 {
+		t.Errorf("kill logged %d times, want 1", n)
+	}
+
+	// the supervisor brings up a new process
+	waitFor(t, "restart", func() bool { return logs.FilterMessage("plugin started").Len() >= 2 })
+}
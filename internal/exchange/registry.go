@@ -0,0 +1,70 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Factory builds an exchange client from its config.
+type Factory func(config Config, logger *zap.Logger) (Exchange, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes an adapter available under name. It is meant to be called
+// from the adapter package's init and panics on duplicate names.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("exchange: register nil factory for " + name)
+	}
+	if _, ok := registry[name]; ok {
+		panic("exchange: register called twice for " + name)
+	}
+
+	registry[name] = factory
+}
+
+// New builds the adapter registered under name. config.Name defaults to
+// name.
+func New(name string, config Config, logger *zap.Logger) (Exchange, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange %q", name)
+	}
+
+	if config.Name == "" {
+		config.Name = name
+	}
+
+	ex, err := factory(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("create exchange %q: %w", config.Name, err)
+	}
+
+	return ex, nil
+}
+
+// Registered returns the sorted names of all registered adapters.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
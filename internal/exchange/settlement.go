@@ -0,0 +1,26 @@
+package exchange
+
+import "time"
+
+// NextSettlement returns the next funding time for venues that settle on a
+// fixed UTC aligned schedule, e.g. every hour on the hour.
+func NextSettlement(now time.Time, interval time.Duration) *time.Time {
+	if interval <= 0 {
+		return nil
+	}
+
+	next := now.UTC().Truncate(interval).Add(interval)
+
+	return &next
+}
+
+// UnixMilli converts a venue timestamp in milliseconds, zero gives nil.
+func UnixMilli(ms int64) *time.Time {
+	if ms <= 0 {
+		return nil
+	}
+
+	t := time.UnixMilli(ms).UTC()
+
+	return &t
+}
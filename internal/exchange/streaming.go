@@ -0,0 +1,187 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// StreamingExchange is implemented by exchanges that push funding updates.
+type StreamingExchange interface {
+	Exchange
+	// Stream sends updates to out until ctx is done. Implementations
+	// reconnect on their own and only return once ctx is done.
+	Stream(ctx context.Context, out chan<- domain.FundingRate) error
+}
+
+// WSConfig describes a websocket feed for RunWebSocket.
+type WSConfig struct {
+	URL   string
+	Proxy string
+	// Subscribe returns the messages sent after every (re)connect.
+	Subscribe func(ctx context.Context) ([]any, error)
+	// Handle is called for every received message.
+	Handle func(msg []byte) error
+	// PingInterval is how often a heartbeat is sent. PingMessage is sent
+	// as a JSON text frame when set, a websocket ping otherwise.
+	PingInterval time.Duration
+	PingMessage  any
+	// ReadTimeout drops the connection when nothing was received for
+	// that long.
+	ReadTimeout time.Duration
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (c WSConfig) withDefaults() WSConfig {
+	if c.PingInterval <= 0 {
+		c.PingInterval = 15 * time.Second
+	}
+	if c.ReadTimeout <= 0 {
+		c.ReadTimeout = 3 * c.PingInterval
+	}
+	if c.MinBackoff <= 0 {
+		c.MinBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Minute
+	}
+
+	return c
+}
+
+// RunWebSocket keeps a websocket feed connected until ctx is done. It
+// reconnects with jittered backoff, resubscribes after every reconnect and
+// sends heartbeats.
+func RunWebSocket(ctx context.Context, config WSConfig, logger *zap.Logger) error {
+	config = config.withDefaults()
+
+	dialer := *websocket.DefaultDialer
+	if config.Proxy != "" {
+		proxyUrl, err := url.Parse(config.Proxy)
+		if err != nil {
+			return fmt.Errorf("parse proxy: %w", err)
+		}
+		dialer.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	backoff := config.MinBackoff
+	for {
+		started := time.Now()
+		err := runWebSocketOnce(ctx, &dialer, config, logger)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// a connection that lived for a while resets the backoff
+		if time.Since(started) > config.MaxBackoff {
+			backoff = config.MinBackoff
+		}
+
+		delay := backoff/2 + rand.N(backoff/2+1)
+		logger.Warn("websocket disconnected, reconnecting",
+			zap.String("url", config.URL),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil
+		}
+
+		backoff = min(backoff*2, config.MaxBackoff)
+	}
+}
+
+func runWebSocketOnce(ctx context.Context, dialer *websocket.Dialer, config WSConfig, logger *zap.Logger) error {
+	conn, _, err := dialer.DialContext(ctx, config.URL, nil)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	// unblock the reader once ctx is done
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if config.Subscribe != nil {
+		messages, err := config.Subscribe(ctx)
+		if err != nil {
+			return fmt.Errorf("build subscriptions: %w", err)
+		}
+		for _, msg := range messages {
+			if err := conn.WriteJSON(msg); err != nil {
+				return fmt.Errorf("subscribe: %w", err)
+			}
+		}
+	}
+
+	extendDeadline := func() error {
+		return conn.SetReadDeadline(time.Now().Add(config.ReadTimeout))
+	}
+	extendDeadline()
+	conn.SetPongHandler(func(string) error { return extendDeadline() })
+
+	// the heartbeat goroutine is the only writer once subscribed
+	heartbeatErr := make(chan error, 1)
+	heartbeatDone := make(chan struct{})
+	defer close(heartbeatDone)
+
+	go func() {
+		ticker := time.NewTicker(config.PingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				var err error
+				if config.PingMessage != nil {
+					err = conn.WriteJSON(config.PingMessage)
+				} else {
+					err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(config.PingInterval))
+				}
+				if err != nil {
+					heartbeatErr <- fmt.Errorf("heartbeat: %w", err)
+					conn.Close()
+					return
+				}
+			case <-heartbeatDone:
+				return
+			}
+		}
+	}()
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case hbErr := <-heartbeatErr:
+				return hbErr
+			default:
+			}
+			return fmt.Errorf("read: %w", err)
+		}
+
+		extendDeadline()
+
+		if config.Handle != nil {
+			if err := config.Handle(msg); err != nil {
+				if errors.Is(err, context.Canceled) {
+					return err
+				}
+				logger.Warn("failed to handle websocket message",
+					zap.String("url", config.URL),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
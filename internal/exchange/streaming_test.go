@@ -0,0 +1,163 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// wsStub is a websocket server that hands every connection to serve.
+type wsStub struct {
+	*httptest.Server
+	mu          sync.Mutex
+	connections int
+	subscribes  []string
+}
+
+func newWSStub(t *testing.T, serve func(n int, conn *websocket.Conn)) *wsStub {
+	t.Helper()
+
+	stub := &wsStub{}
+	upgrader := websocket.Upgrader{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		stub.mu.Lock()
+		stub.connections++
+		n := stub.connections
+		stub.subscribes = append(stub.subscribes, string(msg))
+		stub.mu.Unlock()
+
+		serve(n, conn)
+	}))
+	t.Cleanup(stub.Close)
+
+	return stub
+}
+
+func (s *wsStub) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func (s *wsStub) snapshot() (int, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.connections, append([]string(nil), s.subscribes...)
+}
+
+func TestRunWebSocketReconnectsAndResubscribes(t *testing.T) {
+	stub := newWSStub(t, func(n int, conn *websocket.Conn) {
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"n":1}`))
+		if n == 1 {
+			// drop the first connection
+			return
+		}
+		// keep later ones open until the client leaves
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- RunWebSocket(ctx, WSConfig{
+			URL: stub.url(),
+			Subscribe: func(context.Context) ([]any, error) {
+				return []any{map[string]string{"op": "subscribe"}}, nil
+			},
+			Handle: func(msg []byte) error {
+				received <- string(msg)
+				return nil
+			},
+			MinBackoff: 10 * time.Millisecond,
+			MaxBackoff: 20 * time.Millisecond,
+		}, zap.NewNop())
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-received:
+		case <-ctx.Done():
+			t.Fatalf("received %d messages before timeout, want 2", i)
+		}
+	}
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("RunWebSocket = %v, want nil after cancel", err)
+	}
+
+	connections, subscribes := stub.snapshot()
+	if connections < 2 {
+		t.Fatalf("connections = %d, want at least 2", connections)
+	}
+	for i, msg := range subscribes {
+		if strings.TrimSpace(msg) != `{"op":"subscribe"}` {
+			t.Errorf("subscription %d = %s", i, msg)
+		}
+	}
+}
+
+func TestRunWebSocketReconnectsOnMissedHeartbeat(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	// the stub never reads again, so pings are not answered
+	stub := newWSStub(t, func(n int, conn *websocket.Conn) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- RunWebSocket(ctx, WSConfig{
+			URL: stub.url(),
+			Subscribe: func(context.Context) ([]any, error) {
+				return []any{"subscribe"}, nil
+			},
+			PingInterval: 20 * time.Millisecond,
+			ReadTimeout:  100 * time.Millisecond,
+			MinBackoff:   10 * time.Millisecond,
+			MaxBackoff:   20 * time.Millisecond,
+		}, zap.NewNop())
+	}()
+
+	for {
+		if connections, _ := stub.snapshot(); connections >= 2 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("client did not reconnect after the read timeout")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,263 @@
+// Package synthetic implements an exchange that makes up its rates, for
+// demos, load tests and development without network access.
+package synthetic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+// Rate models.
+const (
+	modelRandomWalk    = "random_walk"
+	modelMeanReverting = "mean_reverting"
+)
+
+var errInjected = errors.New("injected failure")
+
+type Client struct {
+	name    string
+	options options
+	logger  *zap.Logger
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	markets []*market
+}
+
+type options struct {
+	Symbols []string `mapstructure:"symbols"`
+	// Prices are start prices by symbol, missing ones are random. Symbols
+	// match case insensitively, viper lowercases the keys.
+	Prices map[string]float64 `mapstructure:"prices"`
+	// Intervals are assigned to the symbols round robin.
+	Intervals []time.Duration `mapstructure:"intervals"`
+	// Model is random_walk or mean_reverting.
+	Model string `mapstructure:"model"`
+	// MeanRate is the hourly rate walks start at and revert to.
+	MeanRate float64 `mapstructure:"mean_rate"`
+	// Volatility is the standard deviation of one hourly rate step.
+	Volatility float64 `mapstructure:"volatility"`
+	// Reversion is the share of the distance to MeanRate closed per fetch.
+	Reversion       float64 `mapstructure:"reversion"`
+	PriceVolatility float64 `mapstructure:"price_volatility"`
+	// Seed makes runs repeatable, zero seeds from the clock.
+	Seed uint64 `mapstructure:"seed"`
+
+	Failures struct {
+		Latency time.Duration `mapstructure:"latency"`
+		// Jitter adds up to this much random latency.
+		Jitter time.Duration `mapstructure:"jitter"`
+		// ErrorRate is the share of fetches that fail.
+		ErrorRate float64 `mapstructure:"error_rate"`
+		// MalformedRate is the share of rows with an unparsable field.
+		MalformedRate float64 `mapstructure:"malformed_rate"`
+	} `mapstructure:"failures"`
+}
+
+type market struct {
+	symbol   string
+	interval time.Duration
+	rate     float64 // hourly
+	price    float64
+	// openInterest and volume are in base currency
+	openInterest float64
+	volume       float64
+}
+
+func init() {
+	exchange.Register("synthetic", func(config exchange.Config, logger *zap.Logger) (exchange.Exchange, error) {
+		return NewClient(config, logger)
+	})
+}
+
+func NewClient(config exchange.Config, logger *zap.Logger) (*Client, error) {
+	options := options{
+		Symbols:         []string{"BTC", "ETH", "SOL", "DOGE", "kPEPE", "WIF", "SUI", "ARB"},
+		Intervals:       []time.Duration{time.Hour, 8 * time.Hour},
+		Model:           modelMeanReverting,
+		MeanRate:        0.0000125,
+		Volatility:      0.000005,
+		Reversion:       0.05,
+		PriceVolatility: 0.002,
+	}
+	if err := config.DecodeOptions(&options); err != nil {
+		return nil, err
+	}
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	seed := options.Seed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	random := rand.New(rand.NewPCG(seed, seed>>1))
+
+	prices := make(map[string]float64, len(options.Prices))
+	for symbol, price := range options.Prices {
+		prices[strings.ToLower(symbol)] = price
+	}
+
+	markets := make([]*market, 0, len(options.Symbols))
+	for i, symbol := range options.Symbols {
+		price, ok := prices[strings.ToLower(symbol)]
+		if !ok || price <= 0 {
+			price = math.Pow(10, random.Float64()*5) // 1 to 100k
+		}
+		markets = append(markets, &market{
+			symbol:       symbol,
+			interval:     options.Intervals[i%len(options.Intervals)],
+			rate:         options.MeanRate,
+			price:        price,
+			openInterest: (1 + random.Float64()*99) * 1e6 / price,
+			volume:       (1 + random.Float64()*99) * 1e7 / price,
+		})
+	}
+
+	return &Client{
+		name:    config.Name,
+		options: options,
+		logger:  logger,
+		rand:    random,
+		markets: markets,
+	}, nil
+}
+
+func (o options) validate() error {
+	var errs []error
+	if len(o.Symbols) == 0 {
+		errs = append(errs, fmt.Errorf("symbols: required"))
+	}
+	if len(o.Intervals) == 0 {
+		errs = append(errs, fmt.Errorf("intervals: required"))
+	}
+	for _, interval := range o.Intervals {
+		if interval <= 0 {
+			errs = append(errs, fmt.Errorf("intervals: %s is not positive", interval))
+		}
+	}
+	if o.Model != modelRandomWalk && o.Model != modelMeanReverting {
+		errs = append(errs, fmt.Errorf("model: unknown model %q, want %s or %s", o.Model, modelRandomWalk, modelMeanReverting))
+	}
+	if o.Reversion < 0 || o.Reversion > 1 {
+		errs = append(errs, fmt.Errorf("reversion: must be between 0 and 1"))
+	}
+	if o.Failures.ErrorRate < 0 || o.Failures.ErrorRate > 1 {
+		errs = append(errs, fmt.Errorf("failures.error_rate: must be between 0 and 1"))
+	}
+	if o.Failures.MalformedRate < 0 || o.Failures.MalformedRate > 1 {
+		errs = append(errs, fmt.Errorf("failures.malformed_rate: must be between 0 and 1"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Name returns the name of the config block, so several synthetic venues
+// can run side by side.
+func (c *Client) Name() string {
+	return c.name
+}
+
+func (c *Client) FetchFundingRates(ctx context.Context) ([]domain.FundingRate, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rand.Float64() < c.options.Failures.ErrorRate {
+		return nil, errInjected
+	}
+
+	now := time.Now()
+	rates := make([]domain.FundingRate, 0, len(c.markets))
+
+	var parser exchange.FieldParser
+	for _, m := range c.markets {
+		c.step(m)
+
+		rate := m.rate * m.interval.Hours()
+		price := m.price
+		if c.rand.Float64() < c.options.Failures.MalformedRate {
+			// go through the parser so the row is quarantined like a
+			// real venue's broken row
+			rate = parser.Float("rate", "NaN?")
+		}
+
+		openInterest := m.openInterest
+		volume := m.volume
+		rates = append(rates, domain.FundingRate{
+			Exchange:        c.Name(),
+			VenueSymbol:     m.symbol,
+			Rate:            rate,
+			FundingInterval: m.interval,
+			Price:           &price,
+			Timestamp:       now,
+			NextFunding:     exchange.NextSettlement(now, m.interval),
+			Market: exchange.Snapshot(
+				exchange.Notional(&openInterest, &price),
+				exchange.Notional(&volume, &price),
+				&price,
+				nil,
+			),
+			Issues: parser.Issues(),
+		})
+	}
+
+	c.logger.Info("fetched funding rates",
+		zap.String("exchange", c.Name()),
+		zap.Int("count", len(rates)),
+	)
+
+	return rates, nil
+}
+
+// step advances the rate and price of m by one fetch.
+func (c *Client) step(m *market) {
+	shock := c.rand.NormFloat64() * c.options.Volatility
+	switch c.options.Model {
+	case modelMeanReverting:
+		m.rate += c.options.Reversion*(c.options.MeanRate-m.rate) + shock
+	default:
+		m.rate += shock
+	}
+
+	m.price *= math.Exp(c.rand.NormFloat64() * c.options.PriceVolatility)
+	m.openInterest *= math.Exp(c.rand.NormFloat64() * 0.01)
+	m.volume *= math.Exp(c.rand.NormFloat64() * 0.01)
+}
+
+// wait injects the configured latency.
+func (c *Client) wait(ctx context.Context) error {
+	latency := c.options.Failures.Latency
+	if jitter := c.options.Failures.Jitter; jitter > 0 {
+		c.mu.Lock()
+		latency += time.Duration(c.rand.Int64N(int64(jitter)))
+		c.mu.Unlock()
+	}
+	if latency <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,27 @@
+package synthetic
+
+import (
+	"testing"
+
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+func TestNewClientMatchesPricesCaseInsensitively(t *testing.T) {
+	// keys arrive lowercased from viper
+	client, err := NewClient(exchange.Config{Name: "synthetic", Options: map[string]any{
+		"symbols": []any{"BTC", "kPEPE"},
+		"prices":  map[string]any{"btc": 65000, "kpepe": 0.01},
+		"seed":    1,
+	}}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	want := map[string]float64{"BTC": 65000, "kPEPE": 0.01}
+	for _, market := range client.markets {
+		if market.price != want[market.symbol] {
+			t.Errorf("%s price = %v, want %v", market.symbol, market.price, want[market.symbol])
+		}
+	}
+}
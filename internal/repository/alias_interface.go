@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+type AliasRepository interface {
+	List(ctx context.Context) ([]domain.SymbolAlias, error)
+	Upsert(ctx context.Context, alias domain.SymbolAlias) error
+	Delete(ctx context.Context, exchange, venueSymbol string) error
+}
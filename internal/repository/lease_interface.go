@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+type LeaseRepository interface {
+	// TryAcquire takes the lease when it is free or expired, or renews it
+	// when holder already has it, and reports whether holder has it now.
+	TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, holder string) error
+	// Get returns the lease, ErrNotFound when it was never taken.
+	Get(ctx context.Context, name string) (domain.Lease, error)
+}
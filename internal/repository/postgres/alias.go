@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type AliasRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewAliasRepository(db *pgxpool.Pool, logger *zap.Logger) *AliasRepository {
+	return &AliasRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a *AliasRepository) List(ctx context.Context) ([]domain.SymbolAlias, error) {
+	q := `
+		SELECT exchange, venue_symbol, symbol, multiplier, created_at
+		FROM symbol_aliases
+		ORDER BY exchange, venue_symbol
+	`
+
+	rows, err := a.db.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("query symbol aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var aliases []domain.SymbolAlias
+	for rows.Next() {
+		var alias domain.SymbolAlias
+		err := rows.Scan(
+			&alias.Exchange,
+			&alias.VenueSymbol,
+			&alias.Symbol,
+			&alias.Multiplier,
+			&alias.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, rows.Err()
+}
+
+func (a *AliasRepository) Upsert(ctx context.Context, alias domain.SymbolAlias) error {
+	q := `
+		INSERT INTO symbol_aliases (exchange, venue_symbol, symbol, multiplier)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (exchange, venue_symbol)
+		DO UPDATE SET symbol = EXCLUDED.symbol, multiplier = EXCLUDED.multiplier
+	`
+
+	_, err := a.db.Exec(ctx, q,
+		alias.Exchange,
+		alias.VenueSymbol,
+		alias.Symbol,
+		alias.Multiplier,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert symbol alias: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AliasRepository) Delete(ctx context.Context, exchange, venueSymbol string) error {
+	q := `DELETE FROM symbol_aliases WHERE exchange = $1 AND venue_symbol = $2`
+
+	tag, err := a.db.Exec(ctx, q, exchange, venueSymbol)
+	if err != nil {
+		return fmt.Errorf("delete symbol alias: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
@@ -26,19 +26,33 @@ func NewFundingRepository(db *pgxpool.Pool, logger *zap.Logger) *FundingReposito
 
 func (f *FundingRepository) Create(ctx context.Context, rate domain.FundingRate) (uuid.UUID, error) {
 	q := `
-		INSERT INTO funding_rates (exchange, symbol, price, rate, timestamp, next_funding)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO funding_rates (
+			exchange, source, symbol, venue_symbol, multiplier, price, rate,
+			long_rate, short_rate, long_borrow_rate, short_borrow_rate,
+			funding_interval, timestamp, next_funding, sample_type, run_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id
 	`
 
 	var id uuid.UUID
 	err := f.db.QueryRow(ctx, q,
 		rate.Exchange,
+		rate.Source,
 		rate.Symbol,
+		rate.VenueSymbol,
+		rate.Multiplier,
 		rate.Price,
 		rate.Rate,
+		rate.LongRate,
+		rate.ShortRate,
+		rate.LongBorrowRate,
+		rate.ShortBorrowRate,
+		rate.FundingInterval,
 		rate.Timestamp,
 		rate.NextFunding,
+		rate.SampleType,
+		rate.RunID,
 	).Scan(&id)
 
 	if err != nil {
@@ -55,25 +69,73 @@ func (f *FundingRepository) CreateBatch(ctx context.Context, rates []domain.Fund
 
 	batch := &pgx.Batch{}
 	q := `
-		INSERT INTO funding_rates (exchange, symbol, price, rate, timestamp, next_funding)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO funding_rates (
+			id, exchange, source, symbol, venue_symbol, multiplier, price, rate,
+			long_rate, short_rate, long_borrow_rate, short_borrow_rate,
+			funding_interval, timestamp, next_funding, sample_type, run_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (exchange, source, venue_symbol, timestamp) WHERE sample_type = 'settled' DO NOTHING
+	`
+	// settled rates stored before are skipped above, so are their snapshots
+	qMarket := `
+		INSERT INTO market_snapshots (id, funding_rate_id, open_interest, volume_24h, mark_price, index_price, premium)
+		SELECT $1::uuid, id, $3::double precision, $4::double precision, $5::double precision, $6::double precision, $7::double precision
+		FROM funding_rates
+		WHERE id = $2
 	`
 
-	for _, rate := range rates {
+	queued := 0
+	for i := range rates {
+		rate := &rates[i]
+		if rate.ID == uuid.Nil {
+			rate.ID = uuid.New()
+		}
+
 		batch.Queue(q,
+			rate.ID,
 			rate.Exchange,
+			rate.Source,
 			rate.Symbol,
+			rate.VenueSymbol,
+			rate.Multiplier,
 			rate.Price,
 			rate.Rate,
+			rate.LongRate,
+			rate.ShortRate,
+			rate.LongBorrowRate,
+			rate.ShortBorrowRate,
+			rate.FundingInterval,
 			rate.Timestamp,
 			rate.NextFunding,
+			rate.SampleType,
+			rate.RunID,
 		)
+		queued++
+
+		if market := rate.Market; market != nil {
+			if market.ID == uuid.Nil {
+				market.ID = uuid.New()
+			}
+			market.FundingRateID = rate.ID
+
+			batch.Queue(qMarket,
+				market.ID,
+				market.FundingRateID,
+				market.OpenInterest,
+				market.Volume24h,
+				market.MarkPrice,
+				market.IndexPrice,
+				market.Premium,
+			)
+			queued++
+		}
 	}
 
 	br := f.db.SendBatch(ctx, batch)
 	defer br.Close()
 
-	for i := range rates {
+	for i := 0; i < queued; i++ {
 		_, err := br.Exec()
 		if err != nil {
 			return fmt.Errorf("batch insert at index %d: %w", i, err)
@@ -88,10 +150,12 @@ func (f *FundingRepository) GetLatest(
 	filter domain.FundingRateFilter,
 ) ([]domain.FundingRate, error) {
 	q := `
-		SELECT DISTINCT ON (exchange, symbol)
-			id, exchange, symbol, price, rate, timestamp, next_funding, created_at
+		SELECT DISTINCT ON (exchange, source, symbol, venue_symbol)
+			id, exchange, source, symbol, venue_symbol, multiplier, price, rate,
+			long_rate, short_rate, long_borrow_rate, short_borrow_rate,
+			funding_interval, timestamp, next_funding, sample_type, run_id, created_at
 		FROM funding_rates
-		WHERE (exchange='lighter' or exchange='backpack')
+		WHERE 1=1
 	`
 
 	args := []any{}
@@ -103,25 +167,51 @@ func (f *FundingRepository) GetLatest(
 		argsCount++
 	}
 
+	if len(filter.Exchanges) > 0 {
+		q += fmt.Sprintf(" AND exchange = ANY($%d)", argsCount)
+		args = append(args, filter.Exchanges)
+		argsCount++
+	}
+
+	if filter.Source != nil {
+		q += fmt.Sprintf(" AND source = $%d", argsCount)
+		args = append(args, *filter.Source)
+		argsCount++
+	}
+
+	if len(filter.Sources) > 0 {
+		q += fmt.Sprintf(" AND source = ANY($%d)", argsCount)
+		args = append(args, filter.Sources)
+		argsCount++
+	}
+
+	if filter.SampleType != nil {
+		q += fmt.Sprintf(" AND sample_type = $%d", argsCount)
+		args = append(args, *filter.SampleType)
+		argsCount++
+	}
+
 	if filter.Symbol != nil {
 		q += fmt.Sprintf(" AND symbol = $%d", argsCount)
 		args = append(args, *filter.Symbol)
 		argsCount++
 	}
 
-	sortBy := "timestamp"
+	sortBy := "latest.timestamp"
 	sortOrder := "DESC"
 
 	if filter.SortBy != "" {
-		validSorts := map[string]bool{
-			"rate":      true,
-			"timestamp": true,
-			"symbol":    true,
-			"exchange":  true,
-			"price":     true,
+		validSorts := map[string]string{
+			"rate":          "latest.rate",
+			"timestamp":     "latest.timestamp",
+			"symbol":        "latest.symbol",
+			"exchange":      "latest.exchange",
+			"price":         "latest.price",
+			"open_interest": "ms.open_interest",
+			"volume_24h":    "ms.volume_24h",
 		}
-		if validSorts[filter.SortBy] {
-			sortBy = filter.SortBy
+		if column, ok := validSorts[filter.SortBy]; ok {
+			sortBy = column
 		}
 	}
 
@@ -129,12 +219,30 @@ func (f *FundingRepository) GetLatest(
 		sortOrder = "ASC"
 	}
 
-	q += " ORDER BY exchange, symbol, timestamp DESC"
+	// distinct contracts can share a canonical symbol, such as PEPE and
+	// 1000PEPE on one venue
+	q += " ORDER BY exchange, source, symbol, venue_symbol, timestamp DESC"
 
 	q = fmt.Sprintf(`
-		SELECT * FROM (%s) as latest
-		ORDER BY %s %s
-	`, q, sortBy, sortOrder)
+		SELECT latest.*, ms.id, ms.open_interest, ms.volume_24h, ms.mark_price, ms.index_price, ms.premium
+		FROM (%s) as latest
+		LEFT JOIN market_snapshots ms ON ms.funding_rate_id = latest.id
+		WHERE 1=1
+	`, q)
+
+	if filter.MinOpenInterest != nil {
+		q += fmt.Sprintf(" AND ms.open_interest >= $%d", argsCount)
+		args = append(args, *filter.MinOpenInterest)
+		argsCount++
+	}
+
+	if filter.MinVolume24h != nil {
+		q += fmt.Sprintf(" AND ms.volume_24h >= $%d", argsCount)
+		args = append(args, *filter.MinVolume24h)
+		argsCount++
+	}
+
+	q += fmt.Sprintf(" ORDER BY %s %s NULLS LAST", sortBy, sortOrder)
 
 	if filter.Limit > 0 {
 		q += fmt.Sprintf(" LIMIT $%d", argsCount)
@@ -156,20 +264,44 @@ func (f *FundingRepository) GetLatest(
 	var rates []domain.FundingRate
 	for rows.Next() {
 		var rate domain.FundingRate
+		var marketID *uuid.UUID
+		var market domain.MarketSnapshot
 		err := rows.Scan(
 			&rate.ID,
 			&rate.Exchange,
+			&rate.Source,
 			&rate.Symbol,
+			&rate.VenueSymbol,
+			&rate.Multiplier,
 			&rate.Price,
 			&rate.Rate,
+			&rate.LongRate,
+			&rate.ShortRate,
+			&rate.LongBorrowRate,
+			&rate.ShortBorrowRate,
+			&rate.FundingInterval,
 			&rate.Timestamp,
 			&rate.NextFunding,
+			&rate.SampleType,
+			&rate.RunID,
 			&rate.CreatedAt,
+			&marketID,
+			&market.OpenInterest,
+			&market.Volume24h,
+			&market.MarkPrice,
+			&market.IndexPrice,
+			&market.Premium,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
+		if marketID != nil {
+			market.ID = *marketID
+			market.FundingRateID = rate.ID
+			rate.Market = &market
+		}
+
 		rates = append(rates, rate)
 	}
 
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// LeaseRepository keeps leases in a table. Expiry is computed with the
+// database clock, so replicas with skewed clocks agree on it.
+type LeaseRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewLeaseRepository(db *pgxpool.Pool, logger *zap.Logger) *LeaseRepository {
+	return &LeaseRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (l *LeaseRepository) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	q := `
+		INSERT INTO leader_leases (name, holder, acquired_at, renewed_at, expires_at)
+		VALUES ($1, $2, now(), now(), now() + $3::interval)
+		ON CONFLICT (name) DO UPDATE SET
+			holder = EXCLUDED.holder,
+			acquired_at = CASE
+				WHEN leader_leases.holder = EXCLUDED.holder THEN leader_leases.acquired_at
+				ELSE EXCLUDED.acquired_at
+			END,
+			renewed_at = EXCLUDED.renewed_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE leader_leases.holder = EXCLUDED.holder OR leader_leases.expires_at < now()
+		RETURNING holder
+	`
+
+	var current string
+	err := l.db.QueryRow(ctx, q, name, holder, ttl).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("acquire lease: %w", err)
+	}
+
+	return current == holder, nil
+}
+
+func (l *LeaseRepository) Release(ctx context.Context, name, holder string) error {
+	q := `DELETE FROM leader_leases WHERE name = $1 AND holder = $2`
+
+	if _, err := l.db.Exec(ctx, q, name, holder); err != nil {
+		return fmt.Errorf("release lease: %w", err)
+	}
+
+	return nil
+}
+
+func (l *LeaseRepository) Get(ctx context.Context, name string) (domain.Lease, error) {
+	q := `
+		SELECT name, holder, acquired_at, renewed_at, expires_at, expires_at > now()
+		FROM leader_leases
+		WHERE name = $1
+	`
+
+	var lease domain.Lease
+	err := l.db.QueryRow(ctx, q, name).Scan(
+		&lease.Name,
+		&lease.Holder,
+		&lease.AcquiredAt,
+		&lease.RenewedAt,
+		&lease.ExpiresAt,
+		&lease.Active,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Lease{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return domain.Lease{}, fmt.Errorf("get lease: %w", err)
+	}
+
+	return lease, nil
+}
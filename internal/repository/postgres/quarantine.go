@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type QuarantineRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewQuarantineRepository(db *pgxpool.Pool, logger *zap.Logger) *QuarantineRepository {
+	return &QuarantineRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (q *QuarantineRepository) CreateBatch(ctx context.Context, rates []domain.QuarantinedRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	query := `
+		INSERT INTO quarantined_rates (exchange, source, venue_symbol, field, raw_value, reason, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	for _, rate := range rates {
+		batch.Queue(query,
+			rate.Exchange,
+			rate.Source,
+			rate.VenueSymbol,
+			rate.Field,
+			rate.RawValue,
+			rate.Reason,
+			rate.Timestamp,
+		)
+	}
+
+	br := q.db.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := range rates {
+		_, err := br.Exec()
+		if err != nil {
+			return fmt.Errorf("batch insert at index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type RunRepository struct {
+	db     *pgxpool.Pool
+	logger *zap.Logger
+}
+
+func NewRunRepository(db *pgxpool.Pool, logger *zap.Logger) *RunRepository {
+	return &RunRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *RunRepository) Start(ctx context.Context, run domain.FetchRun) error {
+	q := `
+		INSERT INTO fetch_runs (id, exchange, sample_type, started_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if _, err := r.db.Exec(ctx, q, run.ID, run.Exchange, run.SampleType, run.StartedAt); err != nil {
+		return fmt.Errorf("insert fetch run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RunRepository) Finish(ctx context.Context, run domain.FetchRun) error {
+	q := `
+		UPDATE fetch_runs
+		SET finished_at = $2, fetched = $3, stored = $4, rejected = $5, error_class = $6, error_message = $7
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, q,
+		run.ID,
+		run.FinishedAt,
+		run.Fetched,
+		run.Stored,
+		run.Rejected,
+		nullable(string(run.ErrorClass)),
+		nullable(run.Error),
+	)
+	if err != nil {
+		return fmt.Errorf("update fetch run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RunRepository) List(ctx context.Context, filter domain.FetchRunFilter) ([]domain.FetchRun, error) {
+	q := `
+		SELECT id, exchange, sample_type, started_at, finished_at, fetched, stored, rejected,
+			COALESCE(error_class, ''), COALESCE(error_message, ''), created_at
+		FROM fetch_runs
+		WHERE 1=1
+	`
+
+	args := []any{}
+	argsCount := 1
+
+	if filter.Exchange != nil {
+		q += fmt.Sprintf(" AND exchange = $%d", argsCount)
+		args = append(args, *filter.Exchange)
+		argsCount++
+	}
+
+	if filter.SampleType != nil {
+		q += fmt.Sprintf(" AND sample_type = $%d", argsCount)
+		args = append(args, *filter.SampleType)
+		argsCount++
+	}
+
+	if filter.ErrorClass != nil {
+		q += fmt.Sprintf(" AND error_class = $%d", argsCount)
+		args = append(args, *filter.ErrorClass)
+		argsCount++
+	}
+
+	if filter.Failed {
+		q += " AND error_class IS NOT NULL"
+	}
+
+	if filter.Since != nil {
+		q += fmt.Sprintf(" AND started_at >= $%d", argsCount)
+		args = append(args, *filter.Since)
+		argsCount++
+	}
+
+	if filter.Until != nil {
+		q += fmt.Sprintf(" AND started_at < $%d", argsCount)
+		args = append(args, *filter.Until)
+		argsCount++
+	}
+
+	q += " ORDER BY started_at DESC"
+
+	if filter.Limit > 0 {
+		q += fmt.Sprintf(" LIMIT $%d", argsCount)
+		args = append(args, filter.Limit)
+		argsCount++
+	}
+
+	if filter.Offset > 0 {
+		q += fmt.Sprintf(" OFFSET $%d", argsCount)
+		args = append(args, filter.Offset)
+	}
+
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query fetch runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []domain.FetchRun{}
+	for rows.Next() {
+		var run domain.FetchRun
+		err := rows.Scan(
+			&run.ID,
+			&run.Exchange,
+			&run.SampleType,
+			&run.StartedAt,
+			&run.FinishedAt,
+			&run.Fetched,
+			&run.Stored,
+			&run.Rejected,
+			&run.ErrorClass,
+			&run.Error,
+			&run.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// nullable maps empty strings to NULL.
+func nullable(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+type QuarantineRepository interface {
+	CreateBatch(ctx context.Context, rates []domain.QuarantinedRate) error
+}
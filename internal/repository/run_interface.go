@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+type RunRepository interface {
+	// Start journals a run before its rows are stored, so they can
+	// reference it.
+	Start(ctx context.Context, run domain.FetchRun) error
+	Finish(ctx context.Context, run domain.FetchRun) error
+	List(ctx context.Context, filter domain.FetchRunFilter) ([]domain.FetchRun, error)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+// Backfill stores the settled funding of symbols since from. Every symbol
+// is journaled as a run of its own, a failing symbol does not stop the
+// others. Settlements stored by an earlier backfill are skipped by the
+// repository, so overlapping windows can be rerun. It returns the number
+// of rows that passed validation.
+func (s *TrackerService) Backfill(ctx context.Context, ex exchange.HistoryExchange, symbols []string, from time.Time) (int, error) {
+	total := 0
+	var errs []error
+
+	for _, symbol := range symbols {
+		run := s.startRun(ctx, ex.Name(), domain.SampleSettled, time.Now())
+
+		rates, err := ex.FetchFundingHistory(ctx, symbol, from)
+		run.Fetched = len(rates)
+		if err != nil {
+			s.finishRun(ctx, run, classifyError(err), err)
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+
+		for i := range rates {
+			rates[i].SampleType = domain.SampleSettled
+			rates[i].RunID = run.ref()
+		}
+
+		valid, rejected, err := s.store(ctx, rates)
+		run.Stored, run.Rejected = len(valid), rejected
+		if err != nil {
+			s.finishRun(ctx, run, domain.RunErrorStore, err)
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+		s.finishRun(ctx, run, "", nil)
+
+		s.logger.Info("backfilled funding history",
+			zap.String("exchange", ex.Name()),
+			zap.String("symbol", symbol),
+			zap.Int("total", len(valid)),
+			zap.Int("rejected", rejected),
+		)
+		total += len(valid)
+	}
+
+	return total, errors.Join(errs...)
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type latestKey struct {
+	exchange    string
+	source      string
+	symbol      string
+	venueSymbol string
+}
+
+// latestRates is the latest rate per exchange, source, symbol and venue
+// symbol, the same rows the latest query of the repository returns.
+// Readers load the map without locking, writers replace it with an
+// updated copy.
+type latestRates struct {
+	mu    sync.Mutex
+	rates atomic.Pointer[map[latestKey]domain.FundingRate]
+}
+
+// merge adds rates that are newer than the ones held for their key. The
+// first merge marks the rates as loaded, even when empty.
+func (l *latestRates) merge(rates []domain.FundingRate) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(rates) == 0 && l.rates.Load() != nil {
+		return
+	}
+
+	next := make(map[latestKey]domain.FundingRate)
+	if current := l.rates.Load(); current != nil {
+		next = make(map[latestKey]domain.FundingRate, len(*current)+len(rates))
+		for key, rate := range *current {
+			next[key] = rate
+		}
+	}
+
+	now := time.Now()
+	for _, rate := range rates {
+		key := latestKey{rate.Exchange, rate.Source, rate.Symbol, rate.VenueSymbol}
+		if held, ok := next[key]; ok && held.Timestamp.After(rate.Timestamp) {
+			continue
+		}
+		// created_at is set by the database, close enough for new rows
+		if rate.CreatedAt.IsZero() {
+			rate.CreatedAt = now
+		}
+		next[key] = rate
+	}
+
+	l.rates.Store(&next)
+}
+
+// market returns a copy of the market snapshot held for the key of rate,
+// nil when there is none. The copy gets new ids once it is stored.
+func (l *latestRates) market(rate domain.FundingRate) *domain.MarketSnapshot {
+	current := l.rates.Load()
+	if current == nil {
+		return nil
+	}
+
+	held, ok := (*current)[latestKey{rate.Exchange, rate.Source, rate.Symbol, rate.VenueSymbol}]
+	if !ok || held.Market == nil {
+		return nil
+	}
+
+	market := *held.Market
+	market.ID = uuid.Nil
+	market.FundingRateID = uuid.Nil
+
+	return &market
+}
+
+// query applies the filter like the repository does. It reports false
+// until the rates were loaded.
+func (l *latestRates) query(filter domain.FundingRateFilter) ([]domain.FundingRate, bool) {
+	current := l.rates.Load()
+	if current == nil {
+		return nil, false
+	}
+
+	rates := make([]domain.FundingRate, 0, len(*current))
+	for _, rate := range *current {
+		if matches(rate, filter) {
+			rates = append(rates, rate)
+		}
+	}
+
+	compare := rateOrder(filter.SortBy)
+	desc := strings.ToUpper(filter.SortOrder) != "ASC"
+	slices.SortFunc(rates, func(a, b domain.FundingRate) int {
+		c := compare(a, b, desc)
+		if c == 0 {
+			c = cmp.Or(
+				cmp.Compare(a.Exchange, b.Exchange),
+				cmp.Compare(a.Source, b.Source),
+				cmp.Compare(a.Symbol, b.Symbol),
+				cmp.Compare(a.VenueSymbol, b.VenueSymbol),
+			)
+		}
+		return c
+	})
+
+	if filter.Offset > 0 {
+		rates = rates[min(filter.Offset, len(rates)):]
+	}
+	if filter.Limit > 0 && filter.Limit < len(rates) {
+		rates = rates[:filter.Limit]
+	}
+
+	return rates, true
+}
+
+func matches(rate domain.FundingRate, filter domain.FundingRateFilter) bool {
+	if filter.Exchange != nil && rate.Exchange != *filter.Exchange {
+		return false
+	}
+	if len(filter.Exchanges) > 0 && !slices.Contains(filter.Exchanges, rate.Exchange) {
+		return false
+	}
+	if filter.Source != nil && rate.Source != *filter.Source {
+		return false
+	}
+	if len(filter.Sources) > 0 && !slices.Contains(filter.Sources, rate.Source) {
+		return false
+	}
+	if filter.Symbol != nil && rate.Symbol != *filter.Symbol {
+		return false
+	}
+
+	// markets without a snapshot fail the thresholds
+	if filter.MinOpenInterest != nil {
+		if rate.Market == nil || rate.Market.OpenInterest == nil || *rate.Market.OpenInterest < *filter.MinOpenInterest {
+			return false
+		}
+	}
+	if filter.MinVolume24h != nil {
+		if rate.Market == nil || rate.Market.Volume24h == nil || *rate.Market.Volume24h < *filter.MinVolume24h {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rateOrder returns the comparison for a sort_by value, by timestamp when
+// it is unknown. Unset values sort last in both directions.
+func rateOrder(sortBy string) func(a, b domain.FundingRate, desc bool) int {
+	ordered := func(c int, desc bool) int {
+		if desc {
+			return -c
+		}
+		return c
+	}
+	optional := func(value func(domain.FundingRate) *float64) func(a, b domain.FundingRate, desc bool) int {
+		return func(a, b domain.FundingRate, desc bool) int {
+			av, bv := value(a), value(b)
+			switch {
+			case av == nil && bv == nil:
+				return 0
+			case av == nil:
+				return 1
+			case bv == nil:
+				return -1
+			}
+			return ordered(cmp.Compare(*av, *bv), desc)
+		}
+	}
+
+	switch sortBy {
+	case "rate":
+		return func(a, b domain.FundingRate, desc bool) int {
+			return ordered(cmp.Compare(a.Rate, b.Rate), desc)
+		}
+	case "symbol":
+		return func(a, b domain.FundingRate, desc bool) int {
+			return ordered(cmp.Compare(a.Symbol, b.Symbol), desc)
+		}
+	case "exchange":
+		return func(a, b domain.FundingRate, desc bool) int {
+			return ordered(cmp.Compare(a.Exchange, b.Exchange), desc)
+		}
+	case "price":
+		return optional(func(r domain.FundingRate) *float64 { return r.Price })
+	case "open_interest":
+		return optional(func(r domain.FundingRate) *float64 {
+			if r.Market == nil {
+				return nil
+			}
+			return r.Market.OpenInterest
+		})
+	case "volume_24h":
+		return optional(func(r domain.FundingRate) *float64 {
+			if r.Market == nil {
+				return nil
+			}
+			return r.Market.Volume24h
+		})
+	default:
+		return func(a, b domain.FundingRate, desc bool) int {
+			return ordered(a.Timestamp.Compare(b.Timestamp), desc)
+		}
+	}
+}
+
+// LoadLatest fills the in-memory latest rates from the repository. It runs
+// at startup, replicas that do not collect call it through FollowLatest.
+func (s *TrackerService) LoadLatest(ctx context.Context) error {
+	rates, err := s.repo.GetLatest(ctx, domain.FundingRateFilter{Sources: s.exchangeNames()})
+	if err != nil {
+		return fmt.Errorf("load latest rates: %w", err)
+	}
+
+	s.latest.merge(rates)
+
+	return nil
+}
+
+// FollowLatest reloads the latest rates every interval while following
+// reports true, so replicas that do not collect serve what the leader
+// stores. It returns when ctx is done.
+func (s *TrackerService) FollowLatest(ctx context.Context, interval time.Duration, following func() bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !following() {
+				continue
+			}
+			if err := s.LoadLatest(ctx); err != nil {
+				s.logger.Warn("failed to refresh latest rates", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// latestFixture has one rate per venue with distinct values in every
+// sortable field. SOL has no price and no market, ETH no volume.
+func latestFixture() *latestRates {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rate := func(exchange, symbol string, rate float64, minutes int, price *float64, market *domain.MarketSnapshot) domain.FundingRate {
+		return domain.FundingRate{
+			Exchange:        exchange,
+			Source:          exchange,
+			Symbol:          symbol,
+			VenueSymbol:     symbol + "-PERP",
+			Rate:            rate,
+			FundingInterval: time.Hour,
+			Price:           price,
+			Timestamp:       t0.Add(time.Duration(minutes) * time.Minute),
+			Market:          market,
+		}
+	}
+
+	latest := &latestRates{}
+	latest.merge([]domain.FundingRate{
+		rate("binance", "BTC", 0.0003, 3, ptr(65000.0), &domain.MarketSnapshot{OpenInterest: ptr(5e8), Volume24h: ptr(1e9)}),
+		rate("bybit", "ETH", -0.0001, 1, ptr(3200.0), &domain.MarketSnapshot{OpenInterest: ptr(2e8)}),
+		rate("hyperliquid", "SOL", 0.0002, 2, nil, nil),
+		rate("okx", "DOGE", 0, 0, ptr(0.15), &domain.MarketSnapshot{OpenInterest: ptr(1e7), Volume24h: ptr(5e7)}),
+	})
+
+	return latest
+}
+
+func TestLatestRatesQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter domain.FundingRateFilter
+		want   []string
+	}{
+		{
+			name: "timestamp by default, newest first",
+			want: []string{"BTC", "SOL", "ETH", "DOGE"},
+		},
+		{
+			name:   "unknown sort_by falls back to timestamp",
+			filter: domain.FundingRateFilter{SortBy: "funding", SortOrder: "asc"},
+			want:   []string{"DOGE", "ETH", "SOL", "BTC"},
+		},
+		{
+			name:   "rate desc",
+			filter: domain.FundingRateFilter{SortBy: "rate"},
+			want:   []string{"BTC", "SOL", "DOGE", "ETH"},
+		},
+		{
+			name:   "rate asc",
+			filter: domain.FundingRateFilter{SortBy: "rate", SortOrder: "ASC"},
+			want:   []string{"ETH", "DOGE", "SOL", "BTC"},
+		},
+		{
+			name:   "symbol asc",
+			filter: domain.FundingRateFilter{SortBy: "symbol", SortOrder: "asc"},
+			want:   []string{"BTC", "DOGE", "ETH", "SOL"},
+		},
+		{
+			name:   "exchange desc",
+			filter: domain.FundingRateFilter{SortBy: "exchange", SortOrder: "desc"},
+			want:   []string{"DOGE", "SOL", "ETH", "BTC"},
+		},
+		{
+			name:   "price desc, missing last",
+			filter: domain.FundingRateFilter{SortBy: "price"},
+			want:   []string{"BTC", "ETH", "DOGE", "SOL"},
+		},
+		{
+			name:   "price asc, missing last",
+			filter: domain.FundingRateFilter{SortBy: "price", SortOrder: "asc"},
+			want:   []string{"DOGE", "ETH", "BTC", "SOL"},
+		},
+		{
+			name:   "open interest desc, no market last",
+			filter: domain.FundingRateFilter{SortBy: "open_interest"},
+			want:   []string{"BTC", "ETH", "DOGE", "SOL"},
+		},
+		{
+			name:   "open interest asc, no market last",
+			filter: domain.FundingRateFilter{SortBy: "open_interest", SortOrder: "asc"},
+			want:   []string{"DOGE", "ETH", "BTC", "SOL"},
+		},
+		{
+			name:   "volume desc, missing ones last by exchange",
+			filter: domain.FundingRateFilter{SortBy: "volume_24h"},
+			want:   []string{"BTC", "DOGE", "ETH", "SOL"},
+		},
+		{
+			name:   "volume asc, missing ones last by exchange",
+			filter: domain.FundingRateFilter{SortBy: "volume_24h", SortOrder: "asc"},
+			want:   []string{"DOGE", "BTC", "ETH", "SOL"},
+		},
+		{
+			name:   "min open interest is inclusive and drops markets without one",
+			filter: domain.FundingRateFilter{MinOpenInterest: ptr(2e8)},
+			want:   []string{"BTC", "ETH"},
+		},
+		{
+			name:   "min volume drops markets without one",
+			filter: domain.FundingRateFilter{MinVolume24h: ptr(1e7)},
+			want:   []string{"BTC", "DOGE"},
+		},
+		{
+			name:   "both thresholds",
+			filter: domain.FundingRateFilter{MinOpenInterest: ptr(1e8), MinVolume24h: ptr(1e8)},
+			want:   []string{"BTC"},
+		},
+		{
+			name:   "exchange and sources",
+			filter: domain.FundingRateFilter{Exchanges: []string{"bybit", "okx", "binance"}, Sources: []string{"okx", "bybit"}},
+			want:   []string{"ETH", "DOGE"},
+		},
+		{
+			name:   "offset and limit",
+			filter: domain.FundingRateFilter{Offset: 1, Limit: 2},
+			want:   []string{"SOL", "ETH"},
+		},
+		{
+			name:   "offset at the end",
+			filter: domain.FundingRateFilter{Offset: 4},
+			want:   []string{},
+		},
+		{
+			name:   "offset past the end",
+			filter: domain.FundingRateFilter{Offset: 10, Limit: 5},
+			want:   []string{},
+		},
+	}
+
+	latest := latestFixture()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, ok := latest.query(tt.filter)
+			if !ok {
+				t.Fatal("query reported the rates as not loaded")
+			}
+
+			got := make([]string, 0, len(rates))
+			for _, rate := range rates {
+				got = append(got, rate.Symbol)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestRatesQueryBeforeLoad(t *testing.T) {
+	latest := &latestRates{}
+	if _, ok := latest.query(domain.FundingRateFilter{}); ok {
+		t.Fatal("query answered before the rates were loaded")
+	}
+
+	// an empty first load still counts as loaded
+	latest.merge(nil)
+	rates, ok := latest.query(domain.FundingRateFilter{})
+	if !ok || len(rates) != 0 {
+		t.Errorf("query = %v, %v after an empty load, want no rates", rates, ok)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLeaseName = "tracker"
+	defaultLeaseTTL  = 15 * time.Second
+	// releaseTimeout bounds the lease release on shutdown.
+	releaseTimeout = 5 * time.Second
+)
+
+type ElectionConfig struct {
+	// Name is the lease replicas compete for.
+	Name string
+	// InstanceID identifies this replica, it defaults to hostname-pid.
+	InstanceID string
+	// LeaseTTL is how long a dead leader keeps the lease. Followers take
+	// over within LeaseTTL plus a third of it.
+	LeaseTTL time.Duration
+}
+
+// LeaderElector lets one replica at a time run the collection loop. The
+// leader renews a lease every third of its TTL and steps down as soon as
+// a renewal fails, or when its last renewal is older than the TTL because
+// the process was stalled. Followers keep trying to take it.
+type LeaderElector struct {
+	repo   repository.LeaseRepository
+	logger *zap.Logger
+	name   string
+	id     string
+	ttl    time.Duration
+	leader atomic.Bool
+}
+
+func NewLeaderElector(repo repository.LeaseRepository, logger *zap.Logger, config ElectionConfig) (*LeaderElector, error) {
+	if config.Name == "" {
+		config.Name = defaultLeaseName
+	}
+	if config.LeaseTTL <= 0 {
+		config.LeaseTTL = defaultLeaseTTL
+	}
+	if config.InstanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("resolve instance id: %w", err)
+		}
+		config.InstanceID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	return &LeaderElector{
+		repo:   repo,
+		logger: logger,
+		name:   config.Name,
+		id:     config.InstanceID,
+		ttl:    config.LeaseTTL,
+	}, nil
+}
+
+// ID returns the instance id of this replica.
+func (e *LeaderElector) ID() string {
+	return e.id
+}
+
+// IsLeader reports whether this replica currently holds the lease.
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Leader returns the current lease, repository.ErrNotFound when no replica
+// ever took it.
+func (e *LeaderElector) Leader(ctx context.Context) (domain.Lease, error) {
+	return e.repo.Get(ctx, e.name)
+}
+
+// Run competes for the lease until ctx is done and runs lead while this
+// replica holds it. lead must return once its context is canceled.
+func (e *LeaderElector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	var cancelLead context.CancelFunc
+	var leadDone chan struct{}
+	// renewedAt is when the last successful attempt started, the lease
+	// is valid for ttl from at least then
+	var renewedAt time.Time
+
+	stepDown := func() {
+		if cancelLead == nil {
+			return
+		}
+		cancelLead()
+		<-leadDone
+		cancelLead = nil
+		e.leader.Store(false)
+	}
+
+	for {
+		// another replica may own the lease already, stop leading before
+		// asking the database
+		if cancelLead != nil && time.Since(renewedAt) > e.ttl {
+			e.logger.Error("leadership lease expired before it was renewed, stepping down",
+				zap.Duration("since_renewal", time.Since(renewedAt)),
+			)
+			stepDown()
+		}
+
+		// a hung attempt must not outlive the lease it tries to renew
+		attemptAt := time.Now()
+		attemptCtx, cancel := context.WithTimeout(ctx, e.ttl/3)
+		acquired, err := e.repo.TryAcquire(attemptCtx, e.name, e.id, e.ttl)
+		cancel()
+		if err == nil && acquired {
+			renewedAt = attemptAt
+		}
+
+		switch {
+		case err != nil && ctx.Err() == nil:
+			// the lease may still be ours, but we can't tell for long
+			if cancelLead != nil {
+				e.logger.Error("failed to renew leadership, stepping down", zap.Error(err))
+			} else {
+				e.logger.Warn("failed to acquire leadership", zap.Error(err))
+			}
+			stepDown()
+		case acquired && cancelLead == nil:
+			e.logger.Info("acquired leadership", zap.String("instance", e.id))
+			e.leader.Store(true)
+
+			var leadCtx context.Context
+			leadCtx, cancelLead = context.WithCancel(ctx)
+			leadDone = make(chan struct{})
+			go func() {
+				defer close(leadDone)
+				lead(leadCtx)
+			}()
+		case !acquired && err == nil && cancelLead != nil:
+			e.logger.Warn("lost leadership", zap.String("instance", e.id))
+			stepDown()
+		}
+
+		select {
+		case <-ctx.Done():
+			wasLeader := cancelLead != nil
+			stepDown()
+			if wasLeader {
+				e.release()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// release frees the lease so a follower takes over without waiting for
+// it to expire.
+func (e *LeaderElector) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	if err := e.repo.Release(ctx, e.name, e.id); err != nil {
+		e.logger.Warn("failed to release leadership", zap.Error(err))
+		return
+	}
+
+	e.logger.Info("released leadership", zap.String("instance", e.id))
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/repository"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeLeases answers the n-th TryAcquire with try.
+type fakeLeases struct {
+	calls atomic.Int32
+	try   func(ctx context.Context, n int) (bool, error)
+}
+
+func (f *fakeLeases) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	return f.try(ctx, int(f.calls.Add(1)))
+}
+
+func (f *fakeLeases) Release(ctx context.Context, name, holder string) error {
+	return nil
+}
+
+func (f *fakeLeases) Get(ctx context.Context, name string) (domain.Lease, error) {
+	return domain.Lease{}, repository.ErrNotFound
+}
+
+// runElector runs an elector on repo and returns the lead context of the
+// first leadership term, the logs and a func that stops the elector.
+func runElector(t *testing.T, repo repository.LeaseRepository, ttl time.Duration) (<-chan context.Context, *observer.ObservedLogs, func()) {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	elector, err := NewLeaderElector(repo, zap.New(core), ElectionConfig{InstanceID: "test", LeaseTTL: ttl})
+	if err != nil {
+		t.Fatalf("NewLeaderElector: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	terms := make(chan context.Context, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		elector.Run(ctx, func(ctx context.Context) {
+			terms <- ctx
+			<-ctx.Done()
+		})
+	}()
+
+	return terms, logs, func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestLeaderElectorBoundsRenewals(t *testing.T) {
+	repo := &fakeLeases{try: func(ctx context.Context, n int) (bool, error) {
+		if n == 1 {
+			return true, nil
+		}
+		// the database stops answering
+		<-ctx.Done()
+		return false, ctx.Err()
+	}}
+
+	terms, logs, stop := runElector(t, repo, 60*time.Millisecond)
+	defer stop()
+
+	lead := <-terms
+	select {
+	case <-lead.Done():
+	case <-time.After(time.Second):
+		t.Fatal("leader kept leading while renewals hung")
+	}
+	if n := logs.FilterMessage("failed to renew leadership, stepping down").Len(); n != 1 {
+		t.Errorf("renewal failure logged %d times, want 1", n)
+	}
+}
+
+func TestLeaderElectorStepsDownWhenLeaseIsStale(t *testing.T) {
+	ttl := 60 * time.Millisecond
+	repo := &fakeLeases{try: func(ctx context.Context, n int) (bool, error) {
+		if n == 2 {
+			// a stall longer than the lease, e.g. a paused process, ends
+			// with a renewal that came too late to count
+			time.Sleep(ttl + ttl/2)
+		}
+		return true, nil
+	}}
+
+	terms, logs, stop := runElector(t, repo, ttl)
+	defer stop()
+
+	lead := <-terms
+	select {
+	case <-lead.Done():
+	case <-time.After(time.Second):
+		t.Fatal("leader kept leading on a stale lease")
+	}
+	if n := logs.FilterMessage("leadership lease expired before it was renewed, stepping down").Len(); n != 1 {
+		t.Errorf("stale lease logged %d times, want 1", n)
+	}
+
+	// the next renewal succeeds and leadership is taken again
+	select {
+	case <-terms:
+	case <-time.After(time.Second):
+		t.Fatal("leadership was not taken again")
+	}
+}
+
+func TestLeaderElectorKeepsLeadingWhileRenewed(t *testing.T) {
+	repo := &fakeLeases{try: func(ctx context.Context, n int) (bool, error) {
+		return true, nil
+	}}
+
+	terms, _, stop := runElector(t, repo, 30*time.Millisecond)
+	defer stop()
+
+	lead := <-terms
+	time.Sleep(200 * time.Millisecond)
+	if err := lead.Err(); err != nil {
+		t.Fatalf("lead context = %v after renewals succeeded", err)
+	}
+	if n := repo.calls.Load(); n < 3 {
+		t.Errorf("TryAcquire called %d times, want renewals every ttl/3", n)
+	}
+
+	stop()
+	if !errors.Is(lead.Err(), context.Canceled) {
+		t.Errorf("lead context = %v after stop, want canceled", lead.Err())
+	}
+}
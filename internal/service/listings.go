@@ -0,0 +1,74 @@
+package service
+
+import (
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/event"
+)
+
+// listingKey is one contract, venues may list several per canonical
+// symbol, e.g. PEPE and 1000PEPE.
+type listingKey struct {
+	exchange    string
+	symbol      string
+	venueSymbol string
+}
+
+// trackListings compares the contracts an adapter returned with the
+// previous fetch and publishes listings and delistings. Contracts missing
+// from an incomplete fetch are not delisted, only complete fetches replace
+// the known set.
+func (s *TrackerService) trackListings(source string, rates []domain.FundingRate, complete bool) {
+	current := make(map[listingKey]struct{}, len(rates))
+	for _, rate := range rates {
+		current[listingKey{rate.Exchange, rate.Symbol, rate.VenueSymbol}] = struct{}{}
+	}
+
+	var listed, delisted []event.Event
+	now := time.Now()
+
+	s.listingsMu.Lock()
+	previous, seen := s.listings[source]
+	if seen {
+		for key := range current {
+			if _, ok := previous[key]; !ok {
+				listed = append(listed, event.SymbolListed{
+					Source:      source,
+					Exchange:    key.exchange,
+					Symbol:      key.symbol,
+					VenueSymbol: key.venueSymbol,
+					At:          now,
+				})
+			}
+		}
+	}
+
+	if complete {
+		if seen {
+			for key := range previous {
+				if _, ok := current[key]; !ok {
+					delisted = append(delisted, event.SymbolDelisted{
+						Source:      source,
+						Exchange:    key.exchange,
+						Symbol:      key.symbol,
+						VenueSymbol: key.venueSymbol,
+						At:          now,
+					})
+				}
+			}
+		}
+		s.listings[source] = current
+	} else if seen {
+		for key := range current {
+			previous[key] = struct{}{}
+		}
+	} else {
+		s.listings[source] = current
+	}
+	s.listingsMu.Unlock()
+
+	for _, e := range append(listed, delisted...) {
+		s.events.Publish(e)
+	}
+}
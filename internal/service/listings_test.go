@@ -0,0 +1,34 @@
+package service
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/event"
+	"go.uber.org/zap"
+)
+
+func TestTrackListingsKeepsContractsOfOneSymbolApart(t *testing.T) {
+	bus := event.NewBus(zap.NewNop())
+	var mu sync.Mutex
+	var delisted []event.SymbolDelisted
+	bus.Subscribe("test", 0, func(e event.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		delisted = append(delisted, e.(event.SymbolDelisted))
+	}, event.TypeSymbolDelisted)
+
+	tracker := NewTrackerService(nil, nil, nil, nil, nil, bus, zap.NewNop(), TrackerConfig{})
+	contract := func(venueSymbol string) domain.FundingRate {
+		return domain.FundingRate{Exchange: "binance", Symbol: "PEPE", VenueSymbol: venueSymbol}
+	}
+
+	tracker.trackListings("binance", []domain.FundingRate{contract("PEPEUSDT"), contract("1000PEPEUSDT")}, true)
+	tracker.trackListings("binance", []domain.FundingRate{contract("PEPEUSDT")}, true)
+	bus.Close()
+
+	if len(delisted) != 1 || delisted[0].VenueSymbol != "1000PEPEUSDT" || delisted[0].Symbol != "PEPE" {
+		t.Errorf("delisted = %+v, want 1000PEPEUSDT only", delisted)
+	}
+}
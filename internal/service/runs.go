@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/event"
+	"github.com/fiensola/funding/internal/exchange"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// runJournalTimeout bounds journal writes, which also happen after the
+// fetch context was canceled.
+const runJournalTimeout = 5 * time.Second
+
+var errNoRates = errors.New("no funding rates fetched")
+
+// fetchRun is a run in progress. Runs the journal failed to record are still
+// tracked but not referenced by their rows, the exchange stats count them
+// and the rows they stored.
+type fetchRun struct {
+	domain.FetchRun
+	journaled bool
+}
+
+// ref returns the id rows of the run point to.
+func (r *fetchRun) ref() *uuid.UUID {
+	if !r.journaled {
+		return nil
+	}
+
+	id := r.ID
+	return &id
+}
+
+// startRun journals a run that started at startedAt.
+func (s *TrackerService) startRun(ctx context.Context, exchange string, sampleType domain.SampleType, startedAt time.Time) *fetchRun {
+	run := &fetchRun{FetchRun: domain.FetchRun{
+		ID:         uuid.New(),
+		Exchange:   exchange,
+		SampleType: sampleType,
+		StartedAt:  startedAt,
+	}}
+
+	ctx, cancel := context.WithTimeout(ctx, runJournalTimeout)
+	defer cancel()
+
+	if err := s.runs.Start(ctx, run.FetchRun); err != nil {
+		s.logger.Warn("failed to journal fetch run",
+			zap.String("exchange", exchange),
+			zap.Error(err),
+		)
+		return run
+	}
+	run.journaled = true
+
+	return run
+}
+
+// finishRun records the outcome of the run, class is empty when it
+// succeeded.
+func (s *TrackerService) finishRun(ctx context.Context, run *fetchRun, class domain.RunErrorClass, err error) {
+	if !run.journaled {
+		s.stats(run.Exchange).recordUnjournaled(run.Stored)
+		if run.Stored > 0 {
+			s.logger.Warn("stored funding rates without a journaled fetch run",
+				zap.String("exchange", run.Exchange),
+				zap.Int("total", run.Stored),
+			)
+		}
+		return
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.ErrorClass = class
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	// the run ends on shutdown too
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), runJournalTimeout)
+	defer cancel()
+
+	if err := s.runs.Finish(ctx, run.FetchRun); err != nil {
+		s.logger.Warn("failed to journal fetch run",
+			zap.String("exchange", run.Exchange),
+			zap.Error(err),
+		)
+	}
+}
+
+// fail finishes a failed run and reports the failure on the event bus.
+func (s *TrackerService) fail(ctx context.Context, run *fetchRun, class domain.RunErrorClass, err error) {
+	s.finishRun(ctx, run, class, err)
+
+	s.events.Publish(event.ExchangeFailed{
+		Exchange:   run.Exchange,
+		SampleType: run.SampleType,
+		RunID:      run.ref(),
+		ErrorClass: class,
+		Err:        err,
+		At:         time.Now(),
+	})
+}
+
+// classifyError maps a fetch error to its run error class, empty for nil.
+func classifyError(err error) domain.RunErrorClass {
+	if err == nil {
+		return ""
+	}
+
+	var partial *exchange.PartialError
+	if errors.As(err, &partial) && !partial.AllFailed() {
+		return domain.RunErrorPartial
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return domain.RunErrorCanceled
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return domain.RunErrorTimeout
+	}
+
+	var statusErr *exchange.StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.Code == http.StatusTooManyRequests {
+			return domain.RunErrorRateLimited
+		}
+		return domain.RunErrorHTTPStatus
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return domain.RunErrorDecode
+	}
+
+	if netErr != nil {
+		return domain.RunErrorNetwork
+	}
+
+	return domain.RunErrorOther
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSettlementBefore = 30 * time.Second
+	defaultSettlementAfter  = 30 * time.Second
+)
+
+// Schedule is how often one exchange is polled.
+type Schedule struct {
+	// Interval defaults to TrackerConfig.Interval.
+	Interval time.Duration
+	// SettlementSampling adds a sample Before every settlement the venue
+	// announces in NextFunding and one After it.
+	SettlementSampling bool
+	Before             time.Duration
+	After              time.Duration
+}
+
+// settlementSample is the next sample planned around a settlement.
+type settlementSample struct {
+	at         time.Time
+	settlement time.Time
+	sampleType domain.SampleType
+}
+
+// schedule returns the schedule of an exchange with defaults applied.
+func (s *TrackerService) schedule(name string) Schedule {
+	schedule := s.schedules[name]
+	if schedule.Interval <= 0 {
+		schedule.Interval = s.interval
+	}
+	if schedule.Before <= 0 {
+		schedule.Before = defaultSettlementBefore
+	}
+	if schedule.After <= 0 {
+		schedule.After = defaultSettlementAfter
+	}
+
+	return schedule
+}
+
+// runSchedule polls one exchange on its interval and, when enabled, around
+// its settlements until ctx is done.
+func (s *TrackerService) runSchedule(ctx context.Context, ex *exchange.CircuitBreaker) {
+	schedule := s.schedule(ex.Name())
+
+	ticker := time.NewTicker(schedule.Interval)
+	defer ticker.Stop()
+
+	// stopped until a settlement is known
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	var next *settlementSample
+	plan := func(rates []domain.FundingRate) {
+		if !schedule.SettlementSampling || next != nil {
+			return
+		}
+		next = planSettlement(rates, schedule, time.Now())
+		if next != nil {
+			timer.Reset(time.Until(next.at))
+			s.logger.Debug("planned settlement sample",
+				zap.String("exchange", ex.Name()),
+				zap.String("sample_type", string(next.sampleType)),
+				zap.Time("at", next.at),
+			)
+		}
+	}
+
+	plan(s.fetchAndStore(ctx, ex, domain.SampleScheduled))
+
+	for {
+		select {
+		case <-ticker.C:
+			plan(s.fetchAndStore(ctx, ex, domain.SampleScheduled))
+		case <-timer.C:
+			sample := *next
+			next = nil
+
+			rates := s.fetchAndStore(ctx, ex, sample.sampleType)
+			if sample.sampleType == domain.SamplePreSettlement {
+				next = &settlementSample{
+					at:         sample.settlement.Add(schedule.After),
+					settlement: sample.settlement,
+					sampleType: domain.SamplePostSettlement,
+				}
+				timer.Reset(time.Until(next.at))
+				continue
+			}
+			plan(rates)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// planSettlement picks the earliest upcoming settlement among rates. When
+// it is too close for the pre settlement sample only the post settlement
+// one is planned.
+func planSettlement(rates []domain.FundingRate, schedule Schedule, now time.Time) *settlementSample {
+	var settlement time.Time
+	for _, rate := range rates {
+		if rate.NextFunding == nil || !rate.NextFunding.After(now) {
+			continue
+		}
+		if settlement.IsZero() || rate.NextFunding.Before(settlement) {
+			settlement = *rate.NextFunding
+		}
+	}
+
+	if settlement.IsZero() {
+		return nil
+	}
+
+	if before := settlement.Add(-schedule.Before); before.After(now) {
+		return &settlementSample{at: before, settlement: settlement, sampleType: domain.SamplePreSettlement}
+	}
+
+	return &settlementSample{at: settlement.Add(schedule.After), settlement: settlement, sampleType: domain.SamplePostSettlement}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+func TestPlanSettlement(t *testing.T) {
+	now := time.Date(2026, 1, 1, 7, 50, 0, 0, time.UTC)
+	defaults := Schedule{Before: 30 * time.Second, After: 30 * time.Second}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		// next funding of every rate relative to now, nil for rates
+		// without one
+		next []*time.Duration
+		// want is the planned sample relative to now, none when wantType
+		// is empty
+		want       time.Duration
+		settlement time.Duration
+		wantType   domain.SampleType
+	}{
+		{
+			name:     "no rates",
+			schedule: defaults,
+		},
+		{
+			name:     "no announced settlement",
+			schedule: defaults,
+			next:     []*time.Duration{nil, nil},
+		},
+		{
+			name:     "settlements in the past or now",
+			schedule: defaults,
+			next:     []*time.Duration{ptr(-time.Minute), ptr(time.Duration(0))},
+		},
+		{
+			name:       "earliest upcoming settlement",
+			schedule:   defaults,
+			next:       []*time.Duration{nil, ptr(10 * time.Minute), ptr(-time.Minute), ptr(5 * time.Minute), ptr(time.Hour)},
+			want:       5*time.Minute - 30*time.Second,
+			settlement: 5 * time.Minute,
+			wantType:   domain.SamplePreSettlement,
+		},
+		{
+			name:       "settlement exactly Before away gets only the post sample",
+			schedule:   defaults,
+			next:       []*time.Duration{ptr(30 * time.Second)},
+			want:       time.Minute,
+			settlement: 30 * time.Second,
+			wantType:   domain.SamplePostSettlement,
+		},
+		{
+			name:       "settlement closer than before",
+			schedule:   defaults,
+			next:       []*time.Duration{ptr(10 * time.Second), ptr(time.Hour)},
+			want:       40 * time.Second,
+			settlement: 10 * time.Second,
+			wantType:   domain.SamplePostSettlement,
+		},
+		{
+			name:       "custom window",
+			schedule:   Schedule{Before: 2 * time.Minute, After: 5 * time.Second},
+			next:       []*time.Duration{ptr(10 * time.Minute)},
+			want:       8 * time.Minute,
+			settlement: 10 * time.Minute,
+			wantType:   domain.SamplePreSettlement,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates := make([]domain.FundingRate, 0, len(tt.next))
+			for _, next := range tt.next {
+				rate := domain.FundingRate{VenueSymbol: "BTCUSDT"}
+				if next != nil {
+					rate.NextFunding = ptr(now.Add(*next))
+				}
+				rates = append(rates, rate)
+			}
+
+			got := planSettlement(rates, tt.schedule, now)
+
+			if tt.wantType == "" {
+				if got != nil {
+					t.Errorf("planned %+v, want nothing", *got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("planned nothing")
+			}
+			if got.sampleType != tt.wantType || !got.at.Equal(now.Add(tt.want)) || !got.settlement.Equal(now.Add(tt.settlement)) {
+				t.Errorf("planned %s at %v for %v, want %s at %v for %v",
+					got.sampleType, got.at.Sub(now), got.settlement.Sub(now),
+					tt.wantType, tt.want, tt.settlement)
+			}
+		})
+	}
+}
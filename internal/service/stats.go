@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/exchange"
+)
+
+// ExchangeStats are counters the tracker keeps per exchange.
+type ExchangeStats struct {
+	PartialReports   int64  `json:"partial_reports"`
+	FailedRequests   int64  `json:"failed_requests"`
+	LastPartialError string `json:"last_partial_error,omitempty"`
+	AcceptedRows     int64  `json:"accepted_rows"`
+	RejectedRows     int64  `json:"rejected_rows"`
+	LastRejectReason string `json:"last_reject_reason,omitempty"`
+	// UnjournaledRuns are fetch runs the journal failed to record,
+	// UnjournaledRows the rows they stored without a run id.
+	UnjournaledRuns int64 `json:"unjournaled_runs"`
+	UnjournaledRows int64 `json:"unjournaled_rows"`
+}
+
+// ExchangeStatus is what the health endpoint reports per exchange.
+type ExchangeStatus struct {
+	exchange.Health
+	ExchangeStats
+}
+
+type exchangeStats struct {
+	mu    sync.Mutex
+	stats ExchangeStats
+}
+
+func (s *TrackerService) stats(name string) *exchangeStats {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+
+	stats, ok := s.exchangeStats[name]
+	if !ok {
+		stats = &exchangeStats{}
+		s.exchangeStats[name] = stats
+	}
+
+	return stats
+}
+
+func (e *exchangeStats) recordPartial(err *exchange.PartialError) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.PartialReports++
+	e.stats.FailedRequests += int64(len(err.Failures))
+	e.stats.LastPartialError = err.Error()
+}
+
+func (e *exchangeStats) recordAccepted() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.AcceptedRows++
+}
+
+func (e *exchangeStats) recordRejected(rate domain.QuarantinedRate) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.RejectedRows++
+	e.stats.LastRejectReason = fmt.Sprintf("%s %s: %s", rate.VenueSymbol, rate.Field, rate.Reason)
+}
+
+func (e *exchangeStats) recordUnjournaled(rows int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.UnjournaledRuns++
+	e.stats.UnjournaledRows += int64(rows)
+}
+
+func (e *exchangeStats) snapshot() ExchangeStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.stats
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/event"
+	"github.com/fiensola/funding/internal/exchange"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultStreamFlushInterval = 5 * time.Second
+	// streamFlushTimeout bounds the last flush on shutdown.
+	streamFlushTimeout = 5 * time.Second
+)
+
+type streamKey struct {
+	exchange    string
+	venueSymbol string
+}
+
+// startStreams subscribes to every streaming exchange. Pushed updates are
+// coalesced per symbol and flushed periodically next to the polling loop.
+// The goroutines are added to wg and return once ctx is done.
+func (s *TrackerService) startStreams(ctx context.Context, wg *sync.WaitGroup) {
+	var streams []exchange.StreamingExchange
+	for _, ex := range s.exchanges {
+		if streaming, ok := ex.Exchange.(exchange.StreamingExchange); ok {
+			streams = append(streams, streaming)
+		}
+	}
+
+	if len(streams) == 0 {
+		return
+	}
+
+	updates := make(chan domain.FundingRate, 1024)
+
+	for _, ex := range streams {
+		s.logger.Info("subscribing to funding stream", zap.String("exchange", ex.Name()))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := ex.Stream(ctx, updates); err != nil {
+				s.logger.Error("funding stream stopped",
+					zap.String("exchange", ex.Name()),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.collectStreams(ctx, updates)
+	}()
+}
+
+func (s *TrackerService) collectStreams(ctx context.Context, updates <-chan domain.FundingRate) {
+	flushEvery := s.flushEvery
+	if flushEvery <= 0 {
+		flushEvery = defaultStreamFlushInterval
+	}
+
+	ticker := time.NewTicker(flushEvery)
+	defer ticker.Stop()
+
+	pending := make(map[streamKey]domain.FundingRate)
+
+	for {
+		select {
+		case rate := <-updates:
+			rate.SampleType = domain.SampleStream
+			pending[streamKey{rate.Exchange, rate.VenueSymbol}] = rate
+		case <-ticker.C:
+			s.flushStreamed(ctx, pending)
+		case <-ctx.Done():
+			// keep what arrived since the last flush
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), streamFlushTimeout)
+			s.flushStreamed(ctx, pending)
+			cancel()
+			return
+		}
+	}
+}
+
+// flushStreamed stores the pending updates as one stream run per adapter
+// and clears them.
+func (s *TrackerService) flushStreamed(ctx context.Context, pending map[streamKey]domain.FundingRate) {
+	if len(pending) == 0 {
+		return
+	}
+
+	bySource := make(map[string][]domain.FundingRate)
+	for _, rate := range pending {
+		// adapters only set the source on rows about other venues
+		source := rate.Source
+		if source == "" {
+			source = rate.Exchange
+		}
+		bySource[source] = append(bySource[source], rate)
+	}
+	clear(pending)
+
+	for source, rates := range bySource {
+		s.storeStreamed(ctx, source, rates)
+	}
+}
+
+// storeStreamed journals and stores the updates one adapter pushed since
+// the last flush.
+func (s *TrackerService) storeStreamed(ctx context.Context, source string, rates []domain.FundingRate) {
+	run := s.startRun(ctx, source, domain.SampleStream, time.Now())
+	run.Fetched = len(rates)
+	for i := range rates {
+		rates[i].RunID = run.ref()
+	}
+
+	valid, rejected, err := s.store(ctx, rates)
+	run.Stored, run.Rejected = len(valid), rejected
+	if err != nil {
+		s.logger.Error("failed to store streamed funding rates",
+			zap.String("exchange", source),
+			zap.Error(err),
+		)
+		s.fail(ctx, run, domain.RunErrorStore, err)
+		return
+	}
+
+	s.events.Publish(event.RatesCollected{
+		Source:     source,
+		SampleType: domain.SampleStream,
+		RunID:      run.ref(),
+		Rates:      valid,
+		At:         time.Now(),
+	})
+
+	s.logger.Debug("stored streamed funding rates",
+		zap.String("exchange", source),
+		zap.Int("total", len(rates)),
+	)
+	s.finishRun(ctx, run, "", nil)
+}
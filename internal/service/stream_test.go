@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/symbol"
+	"go.uber.org/zap"
+)
+
+// memoryRates keeps stored rows in memory.
+type memoryRates struct {
+	mu    sync.Mutex
+	rates []domain.FundingRate
+}
+
+func (m *memoryRates) CreateBatch(ctx context.Context, rates []domain.FundingRate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rates = append(m.rates, rates...)
+	return nil
+}
+
+func (m *memoryRates) GetLatest(ctx context.Context, filter domain.FundingRateFilter) ([]domain.FundingRate, error) {
+	return nil, nil
+}
+
+// memoryRuns journals runs in memory, or fails every Start with startErr.
+type memoryRuns struct {
+	startErr error
+	mu       sync.Mutex
+	finished []domain.FetchRun
+}
+
+func (m *memoryRuns) Start(ctx context.Context, run domain.FetchRun) error {
+	return m.startErr
+}
+
+func (m *memoryRuns) Finish(ctx context.Context, run domain.FetchRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.finished = append(m.finished, run)
+	return nil
+}
+
+func (m *memoryRuns) List(ctx context.Context, filter domain.FetchRunFilter) ([]domain.FetchRun, error) {
+	return nil, nil
+}
+
+func newStreamTracker(runs *memoryRuns) (*TrackerService, *memoryRates) {
+	rates := &memoryRates{}
+	logger := zap.NewNop()
+
+	return NewTrackerService(nil, rates, nil, runs, symbol.NewNormalizer(nil, logger), nil, logger, TrackerConfig{}), rates
+}
+
+func pendingStream() map[streamKey]domain.FundingRate {
+	now := time.Now()
+	rate := func(exchange, source, venueSymbol string) domain.FundingRate {
+		return domain.FundingRate{
+			Exchange:        exchange,
+			Source:          source,
+			VenueSymbol:     venueSymbol,
+			Rate:            0.0001,
+			FundingInterval: time.Hour,
+			Timestamp:       now,
+			SampleType:      domain.SampleStream,
+		}
+	}
+
+	pending := make(map[streamKey]domain.FundingRate)
+	for _, r := range []domain.FundingRate{
+		rate("backpack", "", "BTC_USDC_PERP"),
+		rate("backpack", "", "ETH_USDC_PERP"),
+		rate("binance", "lighter", "BTCUSDT"),
+	} {
+		pending[streamKey{r.Exchange, r.VenueSymbol}] = r
+	}
+
+	return pending
+}
+
+func TestFlushStreamedJournalsOneRunPerSource(t *testing.T) {
+	runs := &memoryRuns{}
+	tracker, stored := newStreamTracker(runs)
+
+	pending := pendingStream()
+	tracker.flushStreamed(context.Background(), pending)
+
+	if len(pending) != 0 {
+		t.Errorf("%d updates still pending after the flush", len(pending))
+	}
+
+	bySource := make(map[string]domain.FetchRun)
+	for _, run := range runs.finished {
+		if run.SampleType != domain.SampleStream {
+			t.Errorf("%s run sample type = %s, want stream", run.Exchange, run.SampleType)
+		}
+		bySource[run.Exchange] = run
+	}
+	want := map[string]int{"backpack": 2, "lighter": 1}
+	if len(bySource) != len(want) {
+		t.Fatalf("runs = %+v, want one each for %v", runs.finished, want)
+	}
+	for source, n := range want {
+		if run := bySource[source]; run.Fetched != n || run.Stored != n {
+			t.Errorf("%s run fetched %d and stored %d, want %d", source, run.Fetched, run.Stored, n)
+		}
+	}
+
+	for _, rate := range stored.rates {
+		run, ok := bySource[rate.Source]
+		if !ok || rate.RunID == nil || *rate.RunID != run.ID {
+			t.Errorf("%s %s run id = %v, want the %s run", rate.Exchange, rate.VenueSymbol, rate.RunID, rate.Source)
+		}
+	}
+}
+
+func TestFlushStreamedCountsUnjournaledRows(t *testing.T) {
+	runs := &memoryRuns{startErr: errors.New("journal unavailable")}
+	tracker, stored := newStreamTracker(runs)
+
+	tracker.flushStreamed(context.Background(), pendingStream())
+
+	if len(stored.rates) != 3 {
+		t.Fatalf("stored %d rows, want 3", len(stored.rates))
+	}
+	for _, rate := range stored.rates {
+		if rate.RunID != nil {
+			t.Errorf("%s run id = %v, want nil without a journaled run", rate.VenueSymbol, rate.RunID)
+		}
+	}
+
+	tests := []struct {
+		source string
+		rows   int64
+	}{
+		{"backpack", 2},
+		{"lighter", 1},
+	}
+	for _, tt := range tests {
+		stats := tracker.stats(tt.source).snapshot()
+		if stats.UnjournaledRuns != 1 || stats.UnjournaledRows != tt.rows {
+			t.Errorf("%s unjournaled runs %d rows %d, want 1 and %d", tt.source, stats.UnjournaledRuns, stats.UnjournaledRows, tt.rows)
+		}
+	}
+}
+
+func TestFlushStreamedKeepsLastPolledMarket(t *testing.T) {
+	tracker, stored := newStreamTracker(&memoryRuns{})
+
+	openInterest := 5e8
+	polled := domain.FundingRate{
+		Exchange:        "backpack",
+		VenueSymbol:     "BTC_USDC_PERP",
+		Rate:            0.0001,
+		FundingInterval: time.Hour,
+		Timestamp:       time.Now().Add(-time.Minute),
+		Market:          &domain.MarketSnapshot{OpenInterest: &openInterest},
+	}
+	if _, _, err := tracker.store(context.Background(), []domain.FundingRate{polled}); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	held := stored.rates[0].Market
+
+	pending := pendingStream()
+	tracker.flushStreamed(context.Background(), pending)
+
+	for _, rate := range stored.rates[1:] {
+		switch rate.VenueSymbol {
+		case "BTC_USDC_PERP":
+			if rate.Market == nil || rate.Market.OpenInterest == nil || *rate.Market.OpenInterest != openInterest {
+				t.Errorf("streamed BTC market = %+v, want the polled one", rate.Market)
+			} else if rate.Market == held {
+				t.Error("streamed BTC shares the snapshot of the polled row")
+			}
+		default:
+			if rate.Market != nil {
+				t.Errorf("streamed %s market = %+v, want none without a polled one", rate.VenueSymbol, rate.Market)
+			}
+		}
+	}
+
+	// the latest row keeps passing the thresholds
+	rates, _ := tracker.latest.query(domain.FundingRateFilter{MinOpenInterest: &openInterest})
+	if len(rates) != 1 || rates[0].SampleType != domain.SampleStream {
+		t.Errorf("latest above the open interest threshold = %+v, want the streamed BTC row", rates)
+	}
+}
@@ -2,35 +2,91 @@ package service
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/event"
 	"github.com/fiensola/funding/internal/exchange"
 	"github.com/fiensola/funding/internal/repository"
+	"github.com/fiensola/funding/internal/symbol"
+	"github.com/fiensola/funding/internal/validation"
 	"go.uber.org/zap"
 )
 
+type TrackerConfig struct {
+	Interval time.Duration
+	Breaker  exchange.BreakerConfig
+	// StreamFlushInterval is how often pushed updates are written.
+	StreamFlushInterval time.Duration
+	Validation          validation.Config
+	// Schedules override Interval and enable settlement sampling per
+	// exchange name.
+	Schedules map[string]Schedule
+}
+
 type TrackerService struct {
-	exchanges []exchange.Exchange
-	repo      repository.FundingRepository
-	logger    *zap.Logger
-	interval  time.Duration
-	stopCh    chan struct{}
+	exchanges  []*exchange.CircuitBreaker
+	repo       repository.FundingRepository
+	quarantine repository.QuarantineRepository
+	runs       repository.RunRepository
+	normalizer *symbol.Normalizer
+	validator  *validation.Validator
+	events     *event.Bus
+	logger     *zap.Logger
+	interval   time.Duration
+	schedules  map[string]Schedule
+	flushEvery time.Duration
+	stopCh     chan struct{}
+	latest     latestRates
+
+	statsMu       sync.Mutex
+	exchangeStats map[string]*exchangeStats
+
+	listingsMu sync.Mutex
+	listings   map[string]map[listingKey]struct{}
 }
 
 func NewTrackerService(
 	exchanges []exchange.Exchange,
 	repo repository.FundingRepository,
+	quarantine repository.QuarantineRepository,
+	runs repository.RunRepository,
+	normalizer *symbol.Normalizer,
+	events *event.Bus,
 	logger *zap.Logger,
-	interval time.Duration,
+	config TrackerConfig,
 ) *TrackerService {
+	breakers := make([]*exchange.CircuitBreaker, 0, len(exchanges))
+	for _, ex := range exchanges {
+		breaker := exchange.NewCircuitBreaker(ex, config.Breaker)
+		breaker.OnStateChange(func(from, to exchange.BreakerState) {
+			logger.Warn("circuit breaker state changed",
+				zap.String("exchange", ex.Name()),
+				zap.String("from", string(from)),
+				zap.String("to", string(to)),
+			)
+		})
+		breakers = append(breakers, breaker)
+	}
+
 	return &TrackerService{
-		exchanges: exchanges,
-		repo:      repo,
-		logger:    logger,
-		interval:  interval,
-		stopCh:    make(chan struct{}),
+		exchanges:  breakers,
+		repo:       repo,
+		quarantine: quarantine,
+		runs:       runs,
+		normalizer: normalizer,
+		validator:  validation.NewValidator(config.Validation),
+		events:     events,
+		logger:     logger,
+		interval:   config.Interval,
+		schedules:  config.Schedules,
+		flushEvery: config.StreamFlushInterval,
+		stopCh:     make(chan struct{}),
+
+		exchangeStats: make(map[string]*exchangeStats),
+		listings:      make(map[string]map[listingKey]struct{}),
 	}
 }
 
@@ -40,78 +96,211 @@ func (s *TrackerService) Start(ctx context.Context) {
 		zap.Int("exchanges", len(s.exchanges)),
 	)
 
-	s.FetchAndStore(ctx)
+	// streams and schedules live as long as the tracker, Start returns
+	// once all of them did
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	ticker := time.NewTicker(s.interval)
-	defer ticker.Stop()
+	wg := sync.WaitGroup{}
+	s.startStreams(ctx, &wg)
 
-	cleanupTicker := time.NewTicker(1 * time.Hour)
-	defer cleanupTicker.Stop()
+	// every exchange runs on its own schedule
+	for _, ex := range s.exchanges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runSchedule(ctx, ex)
+		}()
+	}
 
-	for {
-		select {
-		case <-ticker.C:
-			s.FetchAndStore(ctx)
-		case <-s.stopCh:
-			s.logger.Info("stopping funding tracker")
-			return
-		case <-ctx.Done():
-			s.logger.Info("context canceled, stopping tracker")
-			return
-		}
+	select {
+	case <-s.stopCh:
+		s.logger.Info("stopping funding tracker")
+	case <-ctx.Done():
+		s.logger.Info("context canceled, stopping tracker")
 	}
+
+	cancel()
+	wg.Wait()
 }
 
-func (s *TrackerService) FetchAndStore(ctx context.Context) {
-	wg := sync.WaitGroup{}
-	ratesCh := make(chan []domain.FundingRate, len(s.exchanges))
+// fetchAndStore samples one exchange and stores the rates tagged with
+// sampleType. Every call the circuit breaker lets through is journaled as
+// a fetch run and published on the event bus, skips are only visible in
+// the breaker health. It returns the fetched rates, nil when the fetch
+// failed.
+func (s *TrackerService) fetchAndStore(ctx context.Context, ex exchange.Exchange, sampleType domain.SampleType) []domain.FundingRate {
+	startedAt := time.Now()
+	rates, err := ex.FetchFundingRates(ctx)
+	if errors.Is(err, exchange.ErrBreakerOpen) {
+		s.logger.Debug("skipping exchange, circuit breaker is open",
+			zap.String("exchange", ex.Name()),
+		)
+		return nil
+	}
 
-	for _, ex := range s.exchanges {
-		wg.Add(1)
-		go func(exchange exchange.Exchange) {
-			defer wg.Done()
+	run := s.startRun(ctx, ex.Name(), sampleType, startedAt)
+	run.Fetched = len(rates)
+	var partial *exchange.PartialError
+	if errors.As(err, &partial) && !partial.AllFailed() {
+		s.stats(ex.Name()).recordPartial(partial)
+		s.logger.Warn("partially fetched funding rates",
+			zap.String("exchange", ex.Name()),
+			zap.Int("failed", len(partial.Failures)),
+			zap.Int("total", partial.Total),
+			zap.Error(err),
+		)
+	} else if err != nil {
+		s.logger.Error("failed to fetch funding rates",
+			zap.String("exchange", ex.Name()),
+			zap.Error(err),
+		)
+		s.fail(ctx, run, classifyError(err), err)
+		return nil
+	}
+
+	if len(rates) == 0 {
+		s.logger.Warn("no funding rates fetched", zap.String("exchange", ex.Name()))
+		s.fail(ctx, run, domain.RunErrorEmpty, errNoRates)
+		return nil
+	}
+
+	for i := range rates {
+		rates[i].SampleType = sampleType
+		rates[i].RunID = run.ref()
+	}
+
+	valid, rejected, storeErr := s.store(ctx, rates)
+	run.Stored, run.Rejected = len(valid), rejected
+	// rejected rows still tell which symbols the venue lists
+	s.trackListings(ex.Name(), rates, err == nil)
+	if storeErr != nil {
+		s.logger.Error("failed to store funding rates",
+			zap.String("exchange", ex.Name()),
+			zap.Error(storeErr),
+		)
+		s.fail(ctx, run, domain.RunErrorStore, storeErr)
+		return rates
+	}
+
+	s.events.Publish(event.RatesCollected{
+		Source:     ex.Name(),
+		SampleType: sampleType,
+		RunID:      run.ref(),
+		Rates:      valid,
+		At:         time.Now(),
+	})
+
+	s.logger.Info("successfully updated funding rates",
+		zap.String("exchange", ex.Name()),
+		zap.String("sample_type", string(sampleType)),
+		zap.Int("total", len(valid)),
+	)
+	s.finishRun(ctx, run, classifyError(err), err)
 
-			rates, err := exchange.FetchFundingRates(ctx)
-			if err != nil {
-				s.logger.Error("failed to fetch funding rates",
-					zap.String("exchange", exchange.Name()),
-					zap.Error(err),
-				)
-				return
-			}
+	return rates
+}
 
-			ratesCh <- rates
-		}(ex)
+// store normalizes and validates collected rates, quarantines the rejected
+// ones and persists the rest. It returns the stored rows, none when
+// persisting failed, and the number of rejected ones.
+func (s *TrackerService) store(ctx context.Context, rates []domain.FundingRate) ([]domain.FundingRate, int, error) {
+	// adapters only set the source on rows about other venues
+	for i := range rates {
+		if rates[i].Source == "" {
+			rates[i].Source = rates[i].Exchange
+		}
+		if rates[i].SampleType == "" {
+			rates[i].SampleType = domain.SampleScheduled
+		}
 	}
 
-	// close chan when go-s complete
-	go func() {
-		wg.Wait()
-		close(ratesCh)
-	}()
+	s.normalizer.Apply(rates)
 
-	var allRates []domain.FundingRate
-	for rates := range ratesCh {
-		allRates = append(allRates, rates...)
+	// pushed updates carry no market context, they keep the last polled
+	// one so the latest row of a streaming venue still has it
+	for i := range rates {
+		if rates[i].SampleType == domain.SampleStream && rates[i].Market == nil {
+			rates[i].Market = s.latest.market(rates[i])
+		}
 	}
 
-	if len(allRates) == 0 {
-		s.logger.Warn("no funding rates fetched")
-		return
+	valid, rejected := s.validator.Validate(rates)
+	s.recordValidation(valid, rejected)
+
+	if len(rejected) > 0 {
+		s.logger.Warn("quarantined invalid funding rates", zap.Int("total", len(rejected)))
+
+		if err := s.quarantine.CreateBatch(ctx, rejected); err != nil {
+			s.logger.Error("failed to store quarantined rates", zap.Error(err))
+		}
 	}
 
-	if err := s.repo.CreateBatch(ctx, allRates); err != nil {
-		s.logger.Error("failed to store funding rates", zap.Error(err))
-		return
+	if err := s.repo.CreateBatch(ctx, valid); err != nil {
+		return nil, len(rejected), err
 	}
+	s.latest.merge(valid)
 
-	s.logger.Info("succesfully update funding rates", zap.Int("total", len(allRates)))
+	return valid, len(rejected), nil
+}
+
+func (s *TrackerService) recordValidation(valid []domain.FundingRate, rejected []domain.QuarantinedRate) {
+	for _, rate := range valid {
+		s.stats(rate.Source).recordAccepted()
+	}
+
+	for _, rate := range rejected {
+		s.stats(rate.Source).recordRejected(rate)
+	}
 }
 
 func (s *TrackerService) Stop() {
 	close(s.stopCh)
 }
 
+// GetLatestRates serves the latest rates from memory. Queries for the
+// latest sample of one type, and any query before LoadLatest, go to the
+// repository.
 func (s *TrackerService) GetLatestRates(ctx context.Context, filter domain.FundingRateFilter) ([]domain.FundingRate, error) {
+	// rows about untracked venues are kept when a tracked adapter
+	// supplied them
+	if len(filter.Sources) == 0 {
+		filter.Sources = s.exchangeNames()
+	}
+
+	if filter.SampleType == nil {
+		if rates, ok := s.latest.query(filter); ok {
+			return rates, nil
+		}
+	}
+
 	return s.repo.GetLatest(ctx, filter)
 }
+
+// ListRuns returns the journaled fetch runs, newest first.
+func (s *TrackerService) ListRuns(ctx context.Context, filter domain.FetchRunFilter) ([]domain.FetchRun, error) {
+	return s.runs.List(ctx, filter)
+}
+
+// exchangeNames returns the names of the tracked exchanges.
+func (s *TrackerService) exchangeNames() []string {
+	names := make([]string, 0, len(s.exchanges))
+	for _, ex := range s.exchanges {
+		names = append(names, ex.Name())
+	}
+
+	return names
+}
+
+// Health returns the breaker state and counters of every tracked exchange.
+func (s *TrackerService) Health() []ExchangeStatus {
+	health := make([]ExchangeStatus, 0, len(s.exchanges))
+	for _, ex := range s.exchanges {
+		health = append(health, ExchangeStatus{
+			Health:        ex.Health(),
+			ExchangeStats: s.stats(ex.Name()).snapshot(),
+		})
+	}
+
+	return health
+}
@@ -0,0 +1,81 @@
+package symbol
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// quoteSuffixes are stripped from symbols without separators, e.g. BTCUSDT.
+var quoteSuffixes = []string{"PERP", "USDT", "USDC", "USD"}
+
+// Canonical applies the built-in rules to a venue symbol and returns the base
+// asset with its contract multiplier:
+//
+//	SOL_USDC_PERP -> SOL, 1
+//	BTC-USDT-SWAP -> BTC, 1
+//	1000PEPEUSDT  -> PEPE, 1000
+//	kPEPE         -> PEPE, 1000
+func Canonical(venueSymbol string) (string, float64) {
+	s := strings.TrimSpace(venueSymbol)
+	if i := strings.IndexAny(s, "_-/ "); i > 0 {
+		s = s[:i]
+	}
+
+	multiplier := 1.0
+
+	// lowercase k marks a thousand contract, e.g. kPEPE, kSHIB
+	if len(s) > 1 && s[0] == 'k' && unicode.IsUpper(rune(s[1])) {
+		s = s[1:]
+		multiplier = 1000
+	}
+
+	s = strings.ToUpper(s)
+
+	for _, suffix := range quoteSuffixes {
+		if base, ok := strings.CutSuffix(s, suffix); ok && base != "" {
+			s = base
+			break
+		}
+	}
+
+	if base, ok := strings.CutPrefix(s, "1M"); ok && isAlpha(base) {
+		return base, multiplier * 1_000_000
+	}
+
+	digits := 0
+	for digits < len(s) && s[digits] >= '0' && s[digits] <= '9' {
+		digits++
+	}
+	if digits > 1 && isAlpha(s[digits:]) {
+		if n, err := strconv.Atoi(s[:digits]); err == nil && isPowerOfTen(n) {
+			return s[digits:], multiplier * float64(n)
+		}
+	}
+
+	return s, multiplier
+}
+
+func isAlpha(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isPowerOfTen(n int) bool {
+	if n < 10 {
+		return false
+	}
+	for n%10 == 0 {
+		n /= 10
+	}
+
+	return n == 1
+}
@@ -0,0 +1,52 @@
+package symbol
+
+import "testing"
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		venueSymbol string
+		symbol      string
+		multiplier  float64
+	}{
+		// separators end the base asset
+		{"SOL_USDC_PERP", "SOL", 1},
+		{"BTC-USDT-SWAP", "BTC", 1},
+		{"ETH/USDC", "ETH", 1},
+		{" BTC-PERP ", "BTC", 1},
+
+		// quote suffixes, PERP before the quote
+		{"BTCPERP", "BTC", 1},
+		{"BTCUSDT", "BTC", 1},
+		{"ETHUSDC", "ETH", 1},
+		{"XRPUSD", "XRP", 1},
+		{"btcusdt", "BTC", 1},
+		{"USDT", "USDT", 1},
+
+		// power-of-ten multipliers
+		{"1000PEPEUSDT", "PEPE", 1000},
+		{"10000SATSUSDT", "SATS", 10000},
+		{"1000000MOGUSDT", "MOG", 1_000_000},
+		{"1200ABCUSDT", "1200ABC", 1},
+
+		// a single leading digit is part of the name
+		{"1INCHUSDT", "1INCH", 1},
+
+		// 1M prefix
+		{"1MBABYDOGEUSDT", "BABYDOGE", 1_000_000},
+
+		// lowercase k prefix
+		{"kPEPE", "PEPE", 1000},
+		{"kSHIB-USD", "SHIB", 1000},
+		{"KAVAUSDT", "KAVA", 1},
+		{"kaspa", "KASPA", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.venueSymbol, func(t *testing.T) {
+			symbol, multiplier := Canonical(tt.venueSymbol)
+			if symbol != tt.symbol || multiplier != tt.multiplier {
+				t.Errorf("Canonical(%q) = %s, %v, want %s, %v", tt.venueSymbol, symbol, multiplier, tt.symbol, tt.multiplier)
+			}
+		})
+	}
+}
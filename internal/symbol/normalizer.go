@@ -0,0 +1,121 @@
+package symbol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fiensola/funding/internal/domain"
+	"github.com/fiensola/funding/internal/repository"
+	"go.uber.org/zap"
+)
+
+// AnyExchange is the alias exchange that matches every venue.
+const AnyExchange = "*"
+
+var ErrInvalidAlias = errors.New("invalid alias")
+
+type aliasKey struct {
+	exchange    string
+	venueSymbol string
+}
+
+// Normalizer maps venue symbols to canonical base assets. Aliases stored in
+// the repository win over the built-in rules.
+type Normalizer struct {
+	repo    repository.AliasRepository
+	logger  *zap.Logger
+	aliases atomic.Pointer[map[aliasKey]domain.SymbolAlias]
+}
+
+func NewNormalizer(repo repository.AliasRepository, logger *zap.Logger) *Normalizer {
+	n := &Normalizer{
+		repo:   repo,
+		logger: logger,
+	}
+	n.aliases.Store(&map[aliasKey]domain.SymbolAlias{})
+
+	return n
+}
+
+// Reload refreshes the alias table from the repository.
+func (n *Normalizer) Reload(ctx context.Context) error {
+	list, err := n.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("load aliases: %w", err)
+	}
+
+	aliases := make(map[aliasKey]domain.SymbolAlias, len(list))
+	for _, alias := range list {
+		aliases[aliasKey{alias.Exchange, alias.VenueSymbol}] = alias
+	}
+	n.aliases.Store(&aliases)
+
+	n.logger.Info("symbol aliases loaded", zap.Int("count", len(aliases)))
+
+	return nil
+}
+
+// Apply sets the canonical symbol and multiplier on every rate, keeping the
+// raw venue symbol in VenueSymbol.
+func (n *Normalizer) Apply(rates []domain.FundingRate) {
+	aliases := *n.aliases.Load()
+
+	for i := range rates {
+		rate := &rates[i]
+		if rate.VenueSymbol == "" {
+			rate.VenueSymbol = rate.Symbol
+		}
+
+		alias, ok := aliases[aliasKey{rate.Exchange, rate.VenueSymbol}]
+		if !ok {
+			alias, ok = aliases[aliasKey{AnyExchange, rate.VenueSymbol}]
+		}
+		if ok {
+			rate.Symbol = alias.Symbol
+			rate.Multiplier = alias.Multiplier
+			continue
+		}
+
+		hint := rate.Symbol
+		if hint == "" {
+			hint = rate.VenueSymbol
+		}
+		rate.Symbol, rate.Multiplier = Canonical(hint)
+	}
+}
+
+func (n *Normalizer) List(ctx context.Context) ([]domain.SymbolAlias, error) {
+	return n.repo.List(ctx)
+}
+
+// Save validates and stores an alias, then reloads the table.
+func (n *Normalizer) Save(ctx context.Context, alias domain.SymbolAlias) error {
+	alias.Symbol = strings.ToUpper(strings.TrimSpace(alias.Symbol))
+	if alias.Exchange == "" || alias.VenueSymbol == "" || alias.Symbol == "" {
+		return fmt.Errorf("%w: exchange, venue_symbol and symbol are required", ErrInvalidAlias)
+	}
+	if alias.Multiplier == 0 {
+		alias.Multiplier = 1
+	}
+	if alias.Multiplier < 0 {
+		return fmt.Errorf("%w: multiplier must be positive", ErrInvalidAlias)
+	}
+
+	if err := n.repo.Upsert(ctx, alias); err != nil {
+		return err
+	}
+
+	return n.Reload(ctx)
+}
+
+// Delete removes an alias, then reloads the table.
+func (n *Normalizer) Delete(ctx context.Context, exchange, venueSymbol string) error {
+	if err := n.repo.Delete(ctx, exchange, venueSymbol); err != nil {
+		return err
+	}
+
+	return n.Reload(ctx)
+}
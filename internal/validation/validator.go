@@ -0,0 +1,103 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+// DefaultMaxHourlyRate is the largest accepted funding rate per hour, well
+// above the caps venues enforce.
+const DefaultMaxHourlyRate = 0.04
+
+type Config struct {
+	// MaxHourlyRate rejects rates whose absolute hourly value is larger.
+	MaxHourlyRate float64
+}
+
+// Validator sits between the adapters and the repository and rejects rows
+// that would otherwise be stored as misleading values.
+type Validator struct {
+	config Config
+}
+
+func NewValidator(config Config) *Validator {
+	if config.MaxHourlyRate <= 0 {
+		config.MaxHourlyRate = DefaultMaxHourlyRate
+	}
+
+	return &Validator{
+		config: config,
+	}
+}
+
+// Validate splits rates into valid rows and quarantine records, one record
+// per problem found.
+func (v *Validator) Validate(rates []domain.FundingRate) ([]domain.FundingRate, []domain.QuarantinedRate) {
+	valid := make([]domain.FundingRate, 0, len(rates))
+	var rejected []domain.QuarantinedRate
+
+	for _, rate := range rates {
+		issues := v.check(rate)
+		if len(issues) == 0 {
+			valid = append(valid, rate)
+			continue
+		}
+
+		for _, issue := range issues {
+			rejected = append(rejected, domain.QuarantinedRate{
+				Exchange:    rate.Exchange,
+				Source:      rate.Source,
+				VenueSymbol: rate.VenueSymbol,
+				Field:       issue.Field,
+				RawValue:    issue.Raw,
+				Reason:      issue.Reason,
+				Timestamp:   rate.Timestamp,
+			})
+		}
+	}
+
+	return valid, rejected
+}
+
+func (v *Validator) check(rate domain.FundingRate) []domain.FieldIssue {
+	issues := append([]domain.FieldIssue(nil), rate.Issues...)
+
+	if rate.Exchange == "" {
+		issues = append(issues, domain.FieldIssue{Field: "exchange", Reason: "empty exchange"})
+	}
+
+	if rate.Symbol == "" || rate.VenueSymbol == "" {
+		issues = append(issues, domain.FieldIssue{Field: "symbol", Raw: rate.VenueSymbol, Reason: "empty symbol"})
+	}
+
+	if !isFinite(rate.Rate) {
+		issues = append(issues, domain.FieldIssue{Field: "rate", Raw: format(rate.Rate), Reason: "not a finite number"})
+	} else if hourly := rate.RateIn(domain.RatePerHour); math.Abs(hourly) > v.config.MaxHourlyRate {
+		issues = append(issues, domain.FieldIssue{
+			Field:  "rate",
+			Raw:    format(rate.Rate),
+			Reason: fmt.Sprintf("hourly rate %g out of range ±%g", hourly, v.config.MaxHourlyRate),
+		})
+	}
+
+	if rate.Price != nil && (!isFinite(*rate.Price) || *rate.Price <= 0) {
+		issues = append(issues, domain.FieldIssue{Field: "price", Raw: format(*rate.Price), Reason: "not a positive number"})
+	}
+
+	if rate.Timestamp.IsZero() {
+		issues = append(issues, domain.FieldIssue{Field: "timestamp", Reason: "empty timestamp"})
+	}
+
+	return issues
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+func format(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,172 @@
+package validation
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fiensola/funding/internal/domain"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func validRate() domain.FundingRate {
+	return domain.FundingRate{
+		Exchange:        "binance",
+		Source:          "binance",
+		Symbol:          "BTC",
+		VenueSymbol:     "BTCUSDT",
+		Rate:            0.0001,
+		FundingInterval: 8 * time.Hour,
+		Price:           ptr(65000.0),
+		Timestamp:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(rate *domain.FundingRate)
+		// fields of the quarantine records, none for a valid row
+		fields []string
+	}{
+		{
+			name:   "valid",
+			modify: func(rate *domain.FundingRate) {},
+		},
+		{
+			name:   "NaN rate",
+			modify: func(rate *domain.FundingRate) { rate.Rate = math.NaN() },
+			fields: []string{"rate"},
+		},
+		{
+			name:   "infinite rate",
+			modify: func(rate *domain.FundingRate) { rate.Rate = math.Inf(-1) },
+			fields: []string{"rate"},
+		},
+		{
+			name:   "negative hourly rate within the limit",
+			modify: func(rate *domain.FundingRate) { rate.Rate = -0.3 },
+		},
+		{
+			name:   "hourly rate above the limit",
+			modify: func(rate *domain.FundingRate) { rate.Rate = 0.33 },
+			fields: []string{"rate"},
+		},
+		{
+			name: "rate without interval is hourly",
+			modify: func(rate *domain.FundingRate) {
+				rate.Rate = 0.05
+				rate.FundingInterval = 0
+			},
+			fields: []string{"rate"},
+		},
+		{
+			name:   "empty canonical symbol",
+			modify: func(rate *domain.FundingRate) { rate.Symbol = "" },
+			fields: []string{"symbol"},
+		},
+		{
+			name:   "empty venue symbol",
+			modify: func(rate *domain.FundingRate) { rate.VenueSymbol = "" },
+			fields: []string{"symbol"},
+		},
+		{
+			name:   "empty exchange",
+			modify: func(rate *domain.FundingRate) { rate.Exchange = "" },
+			fields: []string{"exchange"},
+		},
+		{
+			name:   "zero price",
+			modify: func(rate *domain.FundingRate) { rate.Price = ptr(0.0) },
+			fields: []string{"price"},
+		},
+		{
+			name:   "negative price",
+			modify: func(rate *domain.FundingRate) { rate.Price = ptr(-1.0) },
+			fields: []string{"price"},
+		},
+		{
+			name:   "NaN price",
+			modify: func(rate *domain.FundingRate) { rate.Price = ptr(math.NaN()) },
+			fields: []string{"price"},
+		},
+		{
+			name:   "no price",
+			modify: func(rate *domain.FundingRate) { rate.Price = nil },
+		},
+		{
+			name:   "empty timestamp",
+			modify: func(rate *domain.FundingRate) { rate.Timestamp = time.Time{} },
+			fields: []string{"timestamp"},
+		},
+		{
+			name: "one record per problem",
+			modify: func(rate *domain.FundingRate) {
+				rate.Symbol = ""
+				rate.Rate = math.Inf(1)
+				rate.Price = ptr(0.0)
+			},
+			fields: []string{"symbol", "rate", "price"},
+		},
+	}
+
+	validator := NewValidator(Config{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := validRate()
+			tt.modify(&rate)
+
+			valid, rejected := validator.Validate([]domain.FundingRate{rate})
+
+			fields := make([]string, 0, len(rejected))
+			for _, record := range rejected {
+				fields = append(fields, record.Field)
+			}
+			if !slices.Equal(fields, tt.fields) {
+				t.Errorf("quarantined fields = %v, want %v", fields, tt.fields)
+			}
+			if wantValid := len(tt.fields) == 0; (len(valid) == 1) != wantValid {
+				t.Errorf("valid = %d rows, want valid %v", len(valid), wantValid)
+			}
+		})
+	}
+}
+
+func TestValidateQuarantinesAdapterIssues(t *testing.T) {
+	bad := validRate()
+	bad.Source = "lighter"
+	bad.Issues = []domain.FieldIssue{
+		{Field: "price", Raw: "n/a", Reason: "not a number"},
+		{Field: "next_funding", Raw: "-1", Reason: "negative timestamp"},
+	}
+
+	valid, rejected := NewValidator(Config{}).Validate([]domain.FundingRate{validRate(), bad})
+
+	if len(valid) != 1 || valid[0].Source != "binance" {
+		t.Errorf("valid = %+v, want only the row without issues", valid)
+	}
+
+	want := []domain.QuarantinedRate{
+		{Exchange: "binance", Source: "lighter", VenueSymbol: "BTCUSDT", Field: "price", RawValue: "n/a", Reason: "not a number", Timestamp: bad.Timestamp},
+		{Exchange: "binance", Source: "lighter", VenueSymbol: "BTCUSDT", Field: "next_funding", RawValue: "-1", Reason: "negative timestamp", Timestamp: bad.Timestamp},
+	}
+	if !slices.Equal(rejected, want) {
+		t.Errorf("quarantined = %+v, want %+v", rejected, want)
+	}
+}
+
+func TestValidateMaxHourlyRate(t *testing.T) {
+	rate := validRate()
+	rate.Rate = 0.08 // 0.01 per hour
+
+	validator := NewValidator(Config{MaxHourlyRate: 0.005})
+	_, rejected := validator.Validate([]domain.FundingRate{rate})
+
+	if len(rejected) != 1 || rejected[0].RawValue != "0.08" || rejected[0].Reason != "hourly rate 0.01 out of range ±0.005" {
+		t.Errorf("quarantined = %+v, want the rate out of the configured range", rejected)
+	}
+}
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS leader_leases (
+    name VARCHAR(50) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    acquired_at TIMESTAMP NOT NULL,
+    renewed_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_funding_rates_run;
+
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS run_id;
+
+DROP TABLE IF EXISTS fetch_runs;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS fetch_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    exchange VARCHAR(50) NOT NULL,
+    sample_type VARCHAR(20) NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NULL,
+    fetched INTEGER NOT NULL DEFAULT 0,
+    stored INTEGER NOT NULL DEFAULT 0,
+    rejected INTEGER NOT NULL DEFAULT 0,
+    error_class VARCHAR(20) NULL,
+    error_message TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_fetch_runs_exchange ON fetch_runs (exchange, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_fetch_runs_started_at ON fetch_runs (started_at DESC);
+
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS run_id UUID NULL REFERENCES fetch_runs (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_funding_rates_run ON funding_rates (run_id);
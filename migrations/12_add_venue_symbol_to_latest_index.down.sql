@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_latest_rates;
+CREATE INDEX IF NOT EXISTS idx_latest_rates ON funding_rates (exchange, source, symbol, timestamp DESC);
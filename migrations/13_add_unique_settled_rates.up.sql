@@ -0,0 +1,12 @@
+-- keep the first stored copy of settled rates backfilled more than once
+DELETE FROM funding_rates a
+USING funding_rates b
+WHERE a.sample_type = 'settled'
+  AND b.sample_type = 'settled'
+  AND a.exchange = b.exchange
+  AND a.source = b.source
+  AND a.venue_symbol = b.venue_symbol
+  AND a.timestamp = b.timestamp
+  AND (a.created_at, a.id) > (b.created_at, b.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settled_rates ON funding_rates (exchange, source, venue_symbol, timestamp) WHERE sample_type = 'settled';
@@ -0,0 +1 @@
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS funding_interval;
@@ -0,0 +1,5 @@
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS funding_interval INTERVAL NOT NULL DEFAULT INTERVAL '8 hours';
+
+-- venues that settle hourly; lighter rows were already stored as hourly rates
+UPDATE funding_rates SET funding_interval = INTERVAL '1 hour'
+WHERE exchange IN ('lighter', 'pacifica', 'extended');
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS symbol_aliases;
+
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS multiplier;
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS venue_symbol;
@@ -0,0 +1,13 @@
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS venue_symbol VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS multiplier DOUBLE PRECISION NOT NULL DEFAULT 1;
+
+UPDATE funding_rates SET venue_symbol = symbol WHERE venue_symbol = '';
+
+CREATE TABLE IF NOT EXISTS symbol_aliases (
+    exchange VARCHAR(50) NOT NULL,
+    venue_symbol VARCHAR(50) NOT NULL,
+    symbol VARCHAR(50) NOT NULL,
+    multiplier DOUBLE PRECISION NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (exchange, venue_symbol)
+);
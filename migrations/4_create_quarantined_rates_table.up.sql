@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS quarantined_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    exchange VARCHAR(50) NOT NULL,
+    venue_symbol VARCHAR(50) NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    raw_value TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quarantined_exchange ON quarantined_rates (exchange, created_at DESC);
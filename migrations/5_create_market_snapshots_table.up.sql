@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS market_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    funding_rate_id UUID NOT NULL REFERENCES funding_rates (id) ON DELETE CASCADE,
+    open_interest DOUBLE PRECISION NULL,
+    volume_24h DOUBLE PRECISION NULL,
+    mark_price DOUBLE PRECISION NULL,
+    index_price DOUBLE PRECISION NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_market_snapshots_funding_rate ON market_snapshots (funding_rate_id);
@@ -0,0 +1 @@
+ALTER TABLE market_snapshots DROP COLUMN IF EXISTS premium;
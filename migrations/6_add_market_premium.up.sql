@@ -0,0 +1 @@
+ALTER TABLE market_snapshots ADD COLUMN IF NOT EXISTS premium DOUBLE PRECISION NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS short_borrow_rate;
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS long_borrow_rate;
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS short_rate;
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS long_rate;
@@ -0,0 +1,4 @@
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS long_rate DOUBLE PRECISION NULL;
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS short_rate DOUBLE PRECISION NULL;
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS long_borrow_rate DOUBLE PRECISION NULL;
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS short_borrow_rate DOUBLE PRECISION NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE quarantined_rates DROP COLUMN IF EXISTS source;
+
+DROP INDEX IF EXISTS idx_latest_rates;
+CREATE INDEX IF NOT EXISTS idx_latest_rates ON funding_rates (exchange, symbol, timestamp DESC);
+
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS source;
@@ -0,0 +1,8 @@
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS source VARCHAR(50) NULL;
+UPDATE funding_rates SET source = exchange WHERE source IS NULL;
+ALTER TABLE funding_rates ALTER COLUMN source SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_latest_rates;
+CREATE INDEX IF NOT EXISTS idx_latest_rates ON funding_rates (exchange, source, symbol, timestamp DESC);
+
+ALTER TABLE quarantined_rates ADD COLUMN IF NOT EXISTS source VARCHAR(50) NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_settlement_samples;
+
+ALTER TABLE funding_rates DROP COLUMN IF EXISTS sample_type;
@@ -0,0 +1,3 @@
+ALTER TABLE funding_rates ADD COLUMN IF NOT EXISTS sample_type VARCHAR(20) NOT NULL DEFAULT 'scheduled';
+
+CREATE INDEX IF NOT EXISTS idx_settlement_samples ON funding_rates (exchange, symbol, timestamp DESC) WHERE sample_type = 'pre_settlement';
@@ -0,0 +1,807 @@
+// Contract between the tracker and out-of-process exchange adapters.
+//
+// The tracker starts the plugin command with FUNDING_PLUGIN_ADDR set to a
+// unix socket path. The plugin serves ExchangePlugin on that socket until it
+// receives SIGTERM. Plugins written in Go inside this repository can use
+// internal/exchange/plugin.Serve.
+//
+// The Go code next to this file is generated, run make proto after
+// changing it.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: plugin/v1/plugin.proto
+
+package pluginv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Capability int32
+
+const (
+	Capability_CAPABILITY_UNSPECIFIED Capability = 0
+	Capability_CAPABILITY_HISTORY     Capability = 1
+)
+
+// Enum value maps for Capability.
+var (
+	Capability_name = map[int32]string{
+		0: "CAPABILITY_UNSPECIFIED",
+		1: "CAPABILITY_HISTORY",
+	}
+	Capability_value = map[string]int32{
+		"CAPABILITY_UNSPECIFIED": 0,
+		"CAPABILITY_HISTORY":     1,
+	}
+)
+
+func (x Capability) Enum() *Capability {
+	p := new(Capability)
+	*p = x
+	return p
+}
+
+func (x Capability) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Capability) Descriptor() protoreflect.EnumDescriptor {
+	return file_plugin_v1_plugin_proto_enumTypes[0].Descriptor()
+}
+
+func (Capability) Type() protoreflect.EnumType {
+	return &file_plugin_v1_plugin_proto_enumTypes[0]
+}
+
+func (x Capability) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Capability.Descriptor instead.
+func (Capability) EnumDescriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{0}
+}
+
+type DescribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// protocol_version is the contract version the tracker speaks.
+	ProtocolVersion uint32 `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DescribeRequest) Reset() {
+	*x = DescribeRequest{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeRequest) ProtoMessage() {}
+
+func (x *DescribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeRequest.ProtoReflect.Descriptor instead.
+func (*DescribeRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DescribeRequest) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+type DescribeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name is the venue name, informational, rows are stored under the name
+	// of the exchange config block.
+	Name            string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ProtocolVersion uint32       `protobuf:"varint,2,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	Capabilities    []Capability `protobuf:"varint,3,rep,packed,name=capabilities,proto3,enum=funding.plugin.v1.Capability" json:"capabilities,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DescribeResponse) Reset() {
+	*x = DescribeResponse{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeResponse) ProtoMessage() {}
+
+func (x *DescribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeResponse.ProtoReflect.Descriptor instead.
+func (*DescribeResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DescribeResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DescribeResponse) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *DescribeResponse) GetCapabilities() []Capability {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+type FetchFundingRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchFundingRatesRequest) Reset() {
+	*x = FetchFundingRatesRequest{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchFundingRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchFundingRatesRequest) ProtoMessage() {}
+
+func (x *FetchFundingRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchFundingRatesRequest.ProtoReflect.Descriptor instead.
+func (*FetchFundingRatesRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{2}
+}
+
+type FetchFundingRatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Rates []*FundingRate         `protobuf:"bytes,1,rep,name=rates,proto3" json:"rates,omitempty"`
+	// failures lists the requests that failed when only some of them did,
+	// rates then holds the results of the others. A fetch where every
+	// request failed returns an error instead.
+	Failures []*FetchFailure `protobuf:"bytes,2,rep,name=failures,proto3" json:"failures,omitempty"`
+	// total_requests is how many requests the fetch made, set with failures.
+	TotalRequests uint32 `protobuf:"varint,3,opt,name=total_requests,json=totalRequests,proto3" json:"total_requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchFundingRatesResponse) Reset() {
+	*x = FetchFundingRatesResponse{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchFundingRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchFundingRatesResponse) ProtoMessage() {}
+
+func (x *FetchFundingRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchFundingRatesResponse.ProtoReflect.Descriptor instead.
+func (*FetchFundingRatesResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FetchFundingRatesResponse) GetRates() []*FundingRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *FetchFundingRatesResponse) GetFailures() []*FetchFailure {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+func (x *FetchFundingRatesResponse) GetTotalRequests() uint32 {
+	if x != nil {
+		return x.TotalRequests
+	}
+	return 0
+}
+
+// FetchFailure is one failed request of a partial fetch, key names what was
+// requested, such as a symbol.
+type FetchFailure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchFailure) Reset() {
+	*x = FetchFailure{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchFailure) ProtoMessage() {}
+
+func (x *FetchFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchFailure.ProtoReflect.Descriptor instead.
+func (*FetchFailure) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FetchFailure) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *FetchFailure) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type FetchFundingHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	FromUnixMs    int64                  `protobuf:"varint,2,opt,name=from_unix_ms,json=fromUnixMs,proto3" json:"from_unix_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchFundingHistoryRequest) Reset() {
+	*x = FetchFundingHistoryRequest{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchFundingHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchFundingHistoryRequest) ProtoMessage() {}
+
+func (x *FetchFundingHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchFundingHistoryRequest.ProtoReflect.Descriptor instead.
+func (*FetchFundingHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FetchFundingHistoryRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *FetchFundingHistoryRequest) GetFromUnixMs() int64 {
+	if x != nil {
+		return x.FromUnixMs
+	}
+	return 0
+}
+
+type FetchFundingHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rates         []*FundingRate         `protobuf:"bytes,1,rep,name=rates,proto3" json:"rates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchFundingHistoryResponse) Reset() {
+	*x = FetchFundingHistoryResponse{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchFundingHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchFundingHistoryResponse) ProtoMessage() {}
+
+func (x *FetchFundingHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchFundingHistoryResponse.ProtoReflect.Descriptor instead.
+func (*FetchFundingHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FetchFundingHistoryResponse) GetRates() []*FundingRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+// FundingRate mirrors domain.FundingRate. Rates are fractions per
+// funding_interval_seconds, a positive rate means longs pay shorts.
+type FundingRate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// exchange defaults to the plugin's exchange, set it for rows about
+	// other venues.
+	Exchange               string   `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	VenueSymbol            string   `protobuf:"bytes,2,opt,name=venue_symbol,json=venueSymbol,proto3" json:"venue_symbol,omitempty"`
+	Rate                   float64  `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	FundingIntervalSeconds int64    `protobuf:"varint,4,opt,name=funding_interval_seconds,json=fundingIntervalSeconds,proto3" json:"funding_interval_seconds,omitempty"`
+	TimestampUnixMs        int64    `protobuf:"varint,5,opt,name=timestamp_unix_ms,json=timestampUnixMs,proto3" json:"timestamp_unix_ms,omitempty"`
+	NextFundingUnixMs      *int64   `protobuf:"varint,6,opt,name=next_funding_unix_ms,json=nextFundingUnixMs,proto3,oneof" json:"next_funding_unix_ms,omitempty"`
+	Price                  *float64 `protobuf:"fixed64,7,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	OpenInterest           *float64 `protobuf:"fixed64,8,opt,name=open_interest,json=openInterest,proto3,oneof" json:"open_interest,omitempty"`
+	Volume_24H             *float64 `protobuf:"fixed64,9,opt,name=volume_24h,json=volume24h,proto3,oneof" json:"volume_24h,omitempty"`
+	MarkPrice              *float64 `protobuf:"fixed64,10,opt,name=mark_price,json=markPrice,proto3,oneof" json:"mark_price,omitempty"`
+	IndexPrice             *float64 `protobuf:"fixed64,11,opt,name=index_price,json=indexPrice,proto3,oneof" json:"index_price,omitempty"`
+	LongRate               *float64 `protobuf:"fixed64,12,opt,name=long_rate,json=longRate,proto3,oneof" json:"long_rate,omitempty"`
+	ShortRate              *float64 `protobuf:"fixed64,13,opt,name=short_rate,json=shortRate,proto3,oneof" json:"short_rate,omitempty"`
+	LongBorrowRate         *float64 `protobuf:"fixed64,14,opt,name=long_borrow_rate,json=longBorrowRate,proto3,oneof" json:"long_borrow_rate,omitempty"`
+	ShortBorrowRate        *float64 `protobuf:"fixed64,15,opt,name=short_borrow_rate,json=shortBorrowRate,proto3,oneof" json:"short_borrow_rate,omitempty"`
+	// issues are fields the plugin could not parse, such rows are quarantined.
+	Issues        []*FieldIssue `protobuf:"bytes,16,rep,name=issues,proto3" json:"issues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FundingRate) Reset() {
+	*x = FundingRate{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FundingRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FundingRate) ProtoMessage() {}
+
+func (x *FundingRate) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FundingRate.ProtoReflect.Descriptor instead.
+func (*FundingRate) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FundingRate) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *FundingRate) GetVenueSymbol() string {
+	if x != nil {
+		return x.VenueSymbol
+	}
+	return ""
+}
+
+func (x *FundingRate) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *FundingRate) GetFundingIntervalSeconds() int64 {
+	if x != nil {
+		return x.FundingIntervalSeconds
+	}
+	return 0
+}
+
+func (x *FundingRate) GetTimestampUnixMs() int64 {
+	if x != nil {
+		return x.TimestampUnixMs
+	}
+	return 0
+}
+
+func (x *FundingRate) GetNextFundingUnixMs() int64 {
+	if x != nil && x.NextFundingUnixMs != nil {
+		return *x.NextFundingUnixMs
+	}
+	return 0
+}
+
+func (x *FundingRate) GetPrice() float64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *FundingRate) GetOpenInterest() float64 {
+	if x != nil && x.OpenInterest != nil {
+		return *x.OpenInterest
+	}
+	return 0
+}
+
+func (x *FundingRate) GetVolume_24H() float64 {
+	if x != nil && x.Volume_24H != nil {
+		return *x.Volume_24H
+	}
+	return 0
+}
+
+func (x *FundingRate) GetMarkPrice() float64 {
+	if x != nil && x.MarkPrice != nil {
+		return *x.MarkPrice
+	}
+	return 0
+}
+
+func (x *FundingRate) GetIndexPrice() float64 {
+	if x != nil && x.IndexPrice != nil {
+		return *x.IndexPrice
+	}
+	return 0
+}
+
+func (x *FundingRate) GetLongRate() float64 {
+	if x != nil && x.LongRate != nil {
+		return *x.LongRate
+	}
+	return 0
+}
+
+func (x *FundingRate) GetShortRate() float64 {
+	if x != nil && x.ShortRate != nil {
+		return *x.ShortRate
+	}
+	return 0
+}
+
+func (x *FundingRate) GetLongBorrowRate() float64 {
+	if x != nil && x.LongBorrowRate != nil {
+		return *x.LongBorrowRate
+	}
+	return 0
+}
+
+func (x *FundingRate) GetShortBorrowRate() float64 {
+	if x != nil && x.ShortBorrowRate != nil {
+		return *x.ShortBorrowRate
+	}
+	return 0
+}
+
+func (x *FundingRate) GetIssues() []*FieldIssue {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
+type FieldIssue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Raw           string                 `protobuf:"bytes,2,opt,name=raw,proto3" json:"raw,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldIssue) Reset() {
+	*x = FieldIssue{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldIssue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldIssue) ProtoMessage() {}
+
+func (x *FieldIssue) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldIssue.ProtoReflect.Descriptor instead.
+func (*FieldIssue) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FieldIssue) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldIssue) GetRaw() string {
+	if x != nil {
+		return x.Raw
+	}
+	return ""
+}
+
+func (x *FieldIssue) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_plugin_v1_plugin_proto protoreflect.FileDescriptor
+
+const file_plugin_v1_plugin_proto_rawDesc = "" +
+	"\n" +
+	"\x16plugin/v1/plugin.proto\x12\x11funding.plugin.v1\"<\n" +
+	"\x0fDescribeRequest\x12)\n" +
+	"\x10protocol_version\x18\x01 \x01(\rR\x0fprotocolVersion\"\x94\x01\n" +
+	"\x10DescribeResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12)\n" +
+	"\x10protocol_version\x18\x02 \x01(\rR\x0fprotocolVersion\x12A\n" +
+	"\fcapabilities\x18\x03 \x03(\x0e2\x1d.funding.plugin.v1.CapabilityR\fcapabilities\"\x1a\n" +
+	"\x18FetchFundingRatesRequest\"\xb5\x01\n" +
+	"\x19FetchFundingRatesResponse\x124\n" +
+	"\x05rates\x18\x01 \x03(\v2\x1e.funding.plugin.v1.FundingRateR\x05rates\x12;\n" +
+	"\bfailures\x18\x02 \x03(\v2\x1f.funding.plugin.v1.FetchFailureR\bfailures\x12%\n" +
+	"\x0etotal_requests\x18\x03 \x01(\rR\rtotalRequests\"6\n" +
+	"\fFetchFailure\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"V\n" +
+	"\x1aFetchFundingHistoryRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12 \n" +
+	"\ffrom_unix_ms\x18\x02 \x01(\x03R\n" +
+	"fromUnixMs\"S\n" +
+	"\x1bFetchFundingHistoryResponse\x124\n" +
+	"\x05rates\x18\x01 \x03(\v2\x1e.funding.plugin.v1.FundingRateR\x05rates\"\xb7\x06\n" +
+	"\vFundingRate\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12!\n" +
+	"\fvenue_symbol\x18\x02 \x01(\tR\vvenueSymbol\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\x01R\x04rate\x128\n" +
+	"\x18funding_interval_seconds\x18\x04 \x01(\x03R\x16fundingIntervalSeconds\x12*\n" +
+	"\x11timestamp_unix_ms\x18\x05 \x01(\x03R\x0ftimestampUnixMs\x124\n" +
+	"\x14next_funding_unix_ms\x18\x06 \x01(\x03H\x00R\x11nextFundingUnixMs\x88\x01\x01\x12\x19\n" +
+	"\x05price\x18\a \x01(\x01H\x01R\x05price\x88\x01\x01\x12(\n" +
+	"\ropen_interest\x18\b \x01(\x01H\x02R\fopenInterest\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"volume_24h\x18\t \x01(\x01H\x03R\tvolume24h\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"mark_price\x18\n" +
+	" \x01(\x01H\x04R\tmarkPrice\x88\x01\x01\x12$\n" +
+	"\vindex_price\x18\v \x01(\x01H\x05R\n" +
+	"indexPrice\x88\x01\x01\x12 \n" +
+	"\tlong_rate\x18\f \x01(\x01H\x06R\blongRate\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"short_rate\x18\r \x01(\x01H\aR\tshortRate\x88\x01\x01\x12-\n" +
+	"\x10long_borrow_rate\x18\x0e \x01(\x01H\bR\x0elongBorrowRate\x88\x01\x01\x12/\n" +
+	"\x11short_borrow_rate\x18\x0f \x01(\x01H\tR\x0fshortBorrowRate\x88\x01\x01\x125\n" +
+	"\x06issues\x18\x10 \x03(\v2\x1d.funding.plugin.v1.FieldIssueR\x06issuesB\x17\n" +
+	"\x15_next_funding_unix_msB\b\n" +
+	"\x06_priceB\x10\n" +
+	"\x0e_open_interestB\r\n" +
+	"\v_volume_24hB\r\n" +
+	"\v_mark_priceB\x0e\n" +
+	"\f_index_priceB\f\n" +
+	"\n" +
+	"_long_rateB\r\n" +
+	"\v_short_rateB\x13\n" +
+	"\x11_long_borrow_rateB\x14\n" +
+	"\x12_short_borrow_rate\"L\n" +
+	"\n" +
+	"FieldIssue\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x10\n" +
+	"\x03raw\x18\x02 \x01(\tR\x03raw\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason*@\n" +
+	"\n" +
+	"Capability\x12\x1a\n" +
+	"\x16CAPABILITY_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12CAPABILITY_HISTORY\x10\x012\xcb\x02\n" +
+	"\x0eExchangePlugin\x12S\n" +
+	"\bDescribe\x12\".funding.plugin.v1.DescribeRequest\x1a#.funding.plugin.v1.DescribeResponse\x12n\n" +
+	"\x11FetchFundingRates\x12+.funding.plugin.v1.FetchFundingRatesRequest\x1a,.funding.plugin.v1.FetchFundingRatesResponse\x12t\n" +
+	"\x13FetchFundingHistory\x12-.funding.plugin.v1.FetchFundingHistoryRequest\x1a..funding.plugin.v1.FetchFundingHistoryResponseB6Z4github.com/fiensola/funding/proto/plugin/v1;pluginv1b\x06proto3"
+
+var (
+	file_plugin_v1_plugin_proto_rawDescOnce sync.Once
+	file_plugin_v1_plugin_proto_rawDescData []byte
+)
+
+func file_plugin_v1_plugin_proto_rawDescGZIP() []byte {
+	file_plugin_v1_plugin_proto_rawDescOnce.Do(func() {
+		file_plugin_v1_plugin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_plugin_v1_plugin_proto_rawDesc), len(file_plugin_v1_plugin_proto_rawDesc)))
+	})
+	return file_plugin_v1_plugin_proto_rawDescData
+}
+
+var file_plugin_v1_plugin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_plugin_v1_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_plugin_v1_plugin_proto_goTypes = []any{
+	(Capability)(0),                     // 0: funding.plugin.v1.Capability
+	(*DescribeRequest)(nil),             // 1: funding.plugin.v1.DescribeRequest
+	(*DescribeResponse)(nil),            // 2: funding.plugin.v1.DescribeResponse
+	(*FetchFundingRatesRequest)(nil),    // 3: funding.plugin.v1.FetchFundingRatesRequest
+	(*FetchFundingRatesResponse)(nil),   // 4: funding.plugin.v1.FetchFundingRatesResponse
+	(*FetchFailure)(nil),                // 5: funding.plugin.v1.FetchFailure
+	(*FetchFundingHistoryRequest)(nil),  // 6: funding.plugin.v1.FetchFundingHistoryRequest
+	(*FetchFundingHistoryResponse)(nil), // 7: funding.plugin.v1.FetchFundingHistoryResponse
+	(*FundingRate)(nil),                 // 8: funding.plugin.v1.FundingRate
+	(*FieldIssue)(nil),                  // 9: funding.plugin.v1.FieldIssue
+}
+var file_plugin_v1_plugin_proto_depIdxs = []int32{
+	0, // 0: funding.plugin.v1.DescribeResponse.capabilities:type_name -> funding.plugin.v1.Capability
+	8, // 1: funding.plugin.v1.FetchFundingRatesResponse.rates:type_name -> funding.plugin.v1.FundingRate
+	5, // 2: funding.plugin.v1.FetchFundingRatesResponse.failures:type_name -> funding.plugin.v1.FetchFailure
+	8, // 3: funding.plugin.v1.FetchFundingHistoryResponse.rates:type_name -> funding.plugin.v1.FundingRate
+	9, // 4: funding.plugin.v1.FundingRate.issues:type_name -> funding.plugin.v1.FieldIssue
+	1, // 5: funding.plugin.v1.ExchangePlugin.Describe:input_type -> funding.plugin.v1.DescribeRequest
+	3, // 6: funding.plugin.v1.ExchangePlugin.FetchFundingRates:input_type -> funding.plugin.v1.FetchFundingRatesRequest
+	6, // 7: funding.plugin.v1.ExchangePlugin.FetchFundingHistory:input_type -> funding.plugin.v1.FetchFundingHistoryRequest
+	2, // 8: funding.plugin.v1.ExchangePlugin.Describe:output_type -> funding.plugin.v1.DescribeResponse
+	4, // 9: funding.plugin.v1.ExchangePlugin.FetchFundingRates:output_type -> funding.plugin.v1.FetchFundingRatesResponse
+	7, // 10: funding.plugin.v1.ExchangePlugin.FetchFundingHistory:output_type -> funding.plugin.v1.FetchFundingHistoryResponse
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_plugin_v1_plugin_proto_init() }
+func file_plugin_v1_plugin_proto_init() {
+	if File_plugin_v1_plugin_proto != nil {
+		return
+	}
+	file_plugin_v1_plugin_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_v1_plugin_proto_rawDesc), len(file_plugin_v1_plugin_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_plugin_v1_plugin_proto_goTypes,
+		DependencyIndexes: file_plugin_v1_plugin_proto_depIdxs,
+		EnumInfos:         file_plugin_v1_plugin_proto_enumTypes,
+		MessageInfos:      file_plugin_v1_plugin_proto_msgTypes,
+	}.Build()
+	File_plugin_v1_plugin_proto = out.File
+	file_plugin_v1_plugin_proto_goTypes = nil
+	file_plugin_v1_plugin_proto_depIdxs = nil
+}
@@ -0,0 +1,211 @@
+// Contract between the tracker and out-of-process exchange adapters.
+//
+// The tracker starts the plugin command with FUNDING_PLUGIN_ADDR set to a
+// unix socket path. The plugin serves ExchangePlugin on that socket until it
+// receives SIGTERM. Plugins written in Go inside this repository can use
+// internal/exchange/plugin.Serve.
+//
+// The Go code next to this file is generated, run make proto after
+// changing it.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: plugin/v1/plugin.proto
+
+package pluginv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ExchangePlugin_Describe_FullMethodName            = "/funding.plugin.v1.ExchangePlugin/Describe"
+	ExchangePlugin_FetchFundingRates_FullMethodName   = "/funding.plugin.v1.ExchangePlugin/FetchFundingRates"
+	ExchangePlugin_FetchFundingHistory_FullMethodName = "/funding.plugin.v1.ExchangePlugin/FetchFundingHistory"
+)
+
+// ExchangePluginClient is the client API for ExchangePlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExchangePluginClient interface {
+	// Describe is called after every start and reports what the plugin can do.
+	Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error)
+	FetchFundingRates(ctx context.Context, in *FetchFundingRatesRequest, opts ...grpc.CallOption) (*FetchFundingRatesResponse, error)
+	// FetchFundingHistory is only called when Describe reports CAPABILITY_HISTORY.
+	FetchFundingHistory(ctx context.Context, in *FetchFundingHistoryRequest, opts ...grpc.CallOption) (*FetchFundingHistoryResponse, error)
+}
+
+type exchangePluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExchangePluginClient(cc grpc.ClientConnInterface) ExchangePluginClient {
+	return &exchangePluginClient{cc}
+}
+
+func (c *exchangePluginClient) Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DescribeResponse)
+	err := c.cc.Invoke(ctx, ExchangePlugin_Describe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangePluginClient) FetchFundingRates(ctx context.Context, in *FetchFundingRatesRequest, opts ...grpc.CallOption) (*FetchFundingRatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchFundingRatesResponse)
+	err := c.cc.Invoke(ctx, ExchangePlugin_FetchFundingRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *exchangePluginClient) FetchFundingHistory(ctx context.Context, in *FetchFundingHistoryRequest, opts ...grpc.CallOption) (*FetchFundingHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchFundingHistoryResponse)
+	err := c.cc.Invoke(ctx, ExchangePlugin_FetchFundingHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExchangePluginServer is the server API for ExchangePlugin service.
+// All implementations must embed UnimplementedExchangePluginServer
+// for forward compatibility.
+type ExchangePluginServer interface {
+	// Describe is called after every start and reports what the plugin can do.
+	Describe(context.Context, *DescribeRequest) (*DescribeResponse, error)
+	FetchFundingRates(context.Context, *FetchFundingRatesRequest) (*FetchFundingRatesResponse, error)
+	// FetchFundingHistory is only called when Describe reports CAPABILITY_HISTORY.
+	FetchFundingHistory(context.Context, *FetchFundingHistoryRequest) (*FetchFundingHistoryResponse, error)
+	mustEmbedUnimplementedExchangePluginServer()
+}
+
+// UnimplementedExchangePluginServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedExchangePluginServer struct{}
+
+func (UnimplementedExchangePluginServer) Describe(context.Context, *DescribeRequest) (*DescribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Describe not implemented")
+}
+func (UnimplementedExchangePluginServer) FetchFundingRates(context.Context, *FetchFundingRatesRequest) (*FetchFundingRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchFundingRates not implemented")
+}
+func (UnimplementedExchangePluginServer) FetchFundingHistory(context.Context, *FetchFundingHistoryRequest) (*FetchFundingHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchFundingHistory not implemented")
+}
+func (UnimplementedExchangePluginServer) mustEmbedUnimplementedExchangePluginServer() {}
+func (UnimplementedExchangePluginServer) testEmbeddedByValue()                        {}
+
+// UnsafeExchangePluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExchangePluginServer will
+// result in compilation errors.
+type UnsafeExchangePluginServer interface {
+	mustEmbedUnimplementedExchangePluginServer()
+}
+
+func RegisterExchangePluginServer(s grpc.ServiceRegistrar, srv ExchangePluginServer) {
+	// If the following call pancis, it indicates UnimplementedExchangePluginServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ExchangePlugin_ServiceDesc, srv)
+}
+
+func _ExchangePlugin_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangePluginServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangePlugin_Describe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangePluginServer).Describe(ctx, req.(*DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangePlugin_FetchFundingRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchFundingRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangePluginServer).FetchFundingRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangePlugin_FetchFundingRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangePluginServer).FetchFundingRates(ctx, req.(*FetchFundingRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExchangePlugin_FetchFundingHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchFundingHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExchangePluginServer).FetchFundingHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExchangePlugin_FetchFundingHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExchangePluginServer).FetchFundingHistory(ctx, req.(*FetchFundingHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExchangePlugin_ServiceDesc is the grpc.ServiceDesc for ExchangePlugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExchangePlugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "funding.plugin.v1.ExchangePlugin",
+	HandlerType: (*ExchangePluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler:    _ExchangePlugin_Describe_Handler,
+		},
+		{
+			MethodName: "FetchFundingRates",
+			Handler:    _ExchangePlugin_FetchFundingRates_Handler,
+		},
+		{
+			MethodName: "FetchFundingHistory",
+			Handler:    _ExchangePlugin_FetchFundingHistory_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin/v1/plugin.proto",
+}